@@ -2,11 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	config2 "github.com/conductorone/baton-percipio/pkg/config"
 	"github.com/conductorone/baton-percipio/pkg/connector"
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/config"
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/types"
@@ -44,22 +49,147 @@ func main() {
 }
 
 func getConnector(ctx context.Context, v *viper.Viper) (types.ConnectorServer, error) {
+	var err error
 	l := ctxzap.Extract(ctx)
 	limitCourses := v.GetStringSlice(config2.LimitCoursesField.FieldName)
+
+	reportStateDir := v.GetString(config2.ReportStateDirField.FieldName)
+	if reportStateDir == "" {
+		reportStateDir = filepath.Join(os.TempDir(), connectorName)
+	}
+
+	// An explicit report start date takes precedence over the lookback window.
+	reportStartDate, err := config2.ParseReportStartDate(v.GetString(config2.ReportStartDateField.FieldName), time.Now())
+	if err != nil {
+		l.Error("error parsing report start date", zap.Error(err))
+		return nil, err
+	}
+
+	clientOptions := []client.Option{
+		client.WithReportJobStore(reportStateDir),
+		client.WithReportLookback(
+			time.Duration(v.GetInt(config2.ReportLookbackField.FieldName))*24*time.Hour,
+			reportStartDate,
+		),
+		client.WithContentTypes(v.GetStringSlice(config2.ContentTypesField.FieldName)),
+		client.WithReportAudiences(v.GetStringSlice(config2.ReportAudiencesField.FieldName)),
+		client.WithAttributeGroups(v.GetStringSlice(config2.AttributeGroupsField.FieldName)),
+		client.WithAssignmentDueDays(v.GetInt(config2.AssignmentDueDaysField.FieldName)),
+		client.WithDefaultRole(v.GetString(config2.DefaultRoleField.FieldName)),
+		client.WithUserDeletion(v.GetBool(config2.AllowUserDeletionField.FieldName)),
+		client.WithRateLimit(
+			float64(v.GetInt(config2.RateLimitField.FieldName)),
+			v.GetInt(config2.RateLimitBurstField.FieldName),
+		),
+		client.WithMaxRetries(v.GetInt(config2.MaxRetriesField.FieldName)),
+		client.WithReportSlicing(
+			v.GetInt(config2.ReportSlicesField.FieldName),
+			v.GetInt(config2.ReportConcurrencyField.FieldName),
+		),
+		client.WithReportPolling(
+			time.Duration(v.GetInt(config2.ReportPollInitialIntervalField.FieldName))*time.Second,
+			time.Duration(v.GetInt(config2.ReportPollMaxIntervalField.FieldName))*time.Second,
+			time.Duration(v.GetInt(config2.ReportPollTimeoutField.FieldName))*time.Minute,
+		),
+	}
+	if clientId := v.GetString(config2.PercipioClientIdField.FieldName); clientId != "" {
+		clientOptions = append(clientOptions, client.WithClientCredentials(
+			clientId,
+			v.GetString(config2.PercipioClientSecretField.FieldName),
+			v.GetString(config2.PercipioTokenUrlField.FieldName),
+		))
+	}
+
+	incrementalSync := v.GetBool(config2.IncrementalSyncField.FieldName)
+	if incrementalSync {
+		clientOptions = append(clientOptions, client.WithIncrementalSync(
+			time.Duration(v.GetInt(config2.FullSyncIntervalField.FieldName))*time.Hour,
+		))
+	}
+
+	// Incremental syncs merge onto the previous run's grants, so they always need the persistent on-disk store.
+	var statusesStore *client.SqliteStatusesStore
+	if incrementalSync || v.GetString(config2.GrantStoreField.FieldName) == config2.GrantStoreDisk {
+		grantStoreDir := v.GetString(config2.GrantStoreDirField.FieldName)
+		if grantStoreDir == "" {
+			grantStoreDir = filepath.Join(os.TempDir(), connectorName)
+		}
+		statusesStore, err = client.NewSqliteStatusesStore(
+			ctx,
+			grantStoreDir,
+			v.GetString(config2.OrganizationIdField.FieldName),
+			incrementalSync,
+		)
+		if err != nil {
+			l.Error("error creating grant store", zap.Error(err))
+			return nil, err
+		}
+		clientOptions = append(clientOptions, client.WithStatusesStore(statusesStore))
+	}
+
 	cb, err := connector.New(
 		ctx,
+		v.GetString(config2.RegionField.FieldName),
+		v.GetString(config2.BaseUrlField.FieldName),
 		v.GetString(config2.OrganizationIdField.FieldName),
 		v.GetString(config2.ApiTokenField.FieldName),
 		limitCourses,
+		clientOptions...,
 	)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
+		closeStatusesStore(statusesStore)
 		return nil, err
 	}
 	connector, err := connectorbuilder.NewConnector(ctx, cb)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
+		closeStatusesStore(statusesStore)
 		return nil, err
 	}
-	return connector, nil
+	server := &cleanupServer{ConnectorServer: connector, connector: cb}
+	if statusesStore != nil {
+		server.statusesStore = statusesStore
+	}
+	return server, nil
+}
+
+// closeStatusesStore function closes the on-disk grant store when the connector could not be created.
+// It implements the error path of `getConnector`, which would otherwise leave the store's file behind.
+// The function closes the store if one was created.
+// Which keeps a failed startup from leaving learner data on disk.
+// This implementation ignores the close error, since the startup error is the one reported.
+func closeStatusesStore(statusesStore *client.SqliteStatusesStore) {
+	if statusesStore != nil {
+		_ = statusesStore.Close()
+	}
+}
+
+// cleanupServer struct empties the connector's caches and closes the on-disk grant store when the baton-sdk cleans up after a sync.
+// It is used by `getConnector` to wrap the connector server.
+// It holds the wrapped connector server, the connector whose caches to empty and, when the grant store lives on disk, the statuses store to close.
+// This structure organizes the cleanup the connectorbuilder cannot reach, since the caches belong to the connector and the store to the client.
+// Instances are created by `getConnector`.
+type cleanupServer struct {
+	types.ConnectorServer
+	connector     *connector.Connector
+	statusesStore client.StatusesStore
+}
+
+// Cleanup method runs the baton-sdk cleanup, empties the connector's caches and then closes the statuses store.
+// It implements the `Cleanup` method of the `ConnectorServiceServer` interface.
+// The method delegates to the wrapped server, which clears the HTTP caches, resets the connector's user and catalog snapshots, and closes the store.
+// Which makes the next sync of a long-running connector read fresh data, and removes the non-persistent SQLite file, and the learner data in it, once the sync is over.
+// This implementation returns the errors of the SDK cleanup and of closing the store.
+func (s *cleanupServer) Cleanup(ctx context.Context, request *v2.ConnectorServiceCleanupRequest) (*v2.ConnectorServiceCleanupResponse, error) {
+	response, err := s.ConnectorServer.Cleanup(ctx, request)
+	s.connector.Cleanup(ctx)
+	if s.statusesStore == nil {
+		return response, err
+	}
+	closeErr := s.statusesStore.Close()
+	if closeErr != nil {
+		ctxzap.Extract(ctx).Warn("error closing grant store", zap.Error(closeErr))
+	}
+	return response, errors.Join(err, closeErr)
 }
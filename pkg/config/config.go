@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/conductorone/baton-sdk/pkg/field"
 )
 
@@ -8,7 +11,19 @@ var (
 	ApiTokenField = field.StringField(
 		"api-token",
 		field.WithDescription("The Percipio Bearer Token"),
-		field.WithRequired(true),
+	)
+	PercipioClientIdField = field.StringField(
+		"percipio-client-id",
+		field.WithDescription("The Percipio service account client ID, used instead of an API token"),
+	)
+	PercipioClientSecretField = field.StringField(
+		"percipio-client-secret",
+		field.WithDescription("The Percipio service account client secret"),
+		field.WithIsSecret(true),
+	)
+	PercipioTokenUrlField = field.StringField(
+		"percipio-token-url",
+		field.WithDescription("The OAuth2 token endpoint used to mint Percipio service account tokens (defaults to the Skillsoft token endpoint)"),
 	)
 	OrganizationIdField = field.StringField(
 		"organization-id",
@@ -16,6 +31,173 @@ var (
 		field.WithRequired(true),
 	)
 
+	RegionField = field.SelectField(
+		"percipio-region",
+		Regions,
+		field.WithDescription("The Percipio data center hosting the organization: us, eu or ca (defaults to us)"),
+	)
+	BaseUrlField = field.StringField(
+		"percipio-base-url",
+		field.WithDescription("Override the Percipio API base URL, e.g. for a custom endpoint or a local test server"),
+		field.WithString(func(r *field.StringRuler) {
+			r.IsURI()
+		}),
+	)
+
+	ReportPollInitialIntervalField = field.IntField(
+		"report-poll-initial-interval",
+		field.WithDescription("Seconds to wait before the first learning activity report status check"),
+		field.WithDefaultValue(ReportPollInitialIntervalSecondsDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gt(0)
+		}),
+	)
+	ReportPollMaxIntervalField = field.IntField(
+		"report-poll-max-interval",
+		field.WithDescription("Maximum seconds between learning activity report status checks"),
+		field.WithDefaultValue(ReportPollMaxIntervalSecondsDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gt(0)
+		}),
+	)
+	ReportPollTimeoutField = field.IntField(
+		"report-poll-timeout",
+		field.WithDescription("Minutes to wait for a learning activity report before giving up"),
+		field.WithDefaultValue(ReportPollTimeoutMinutesDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gt(0)
+		}),
+	)
+
+	ReportStateDirField = field.StringField(
+		"report-state-dir",
+		field.WithDescription("Directory used to persist in-flight learning activity report jobs between runs (defaults to the system temp directory)"),
+	)
+	GrantStoreField = field.SelectField(
+		"grant-store",
+		GrantStores,
+		field.WithDescription("Where learning activity statuses are kept during a sync: memory or disk (defaults to memory)"),
+	)
+	GrantStoreDirField = field.StringField(
+		"grant-store-dir",
+		field.WithDescription("Directory for the on-disk grant store (defaults to the system temp directory)"),
+	)
+	IncrementalSyncField = field.BoolField(
+		"incremental-sync",
+		field.WithDescription("Only request learning activity since the last successful report and merge it onto a persisted on-disk grant store"),
+	)
+	FullSyncIntervalField = field.IntField(
+		"full-sync-interval",
+		field.WithDescription("Hours between full learning activity rebuilds when incremental sync is enabled"),
+		field.WithDefaultValue(FullSyncIntervalHoursDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gt(0)
+		}),
+	)
+	ReportLookbackField = field.IntField(
+		"report-lookback-days",
+		field.WithDescription("Days of learning activity to include in the report, ignored when report-start-date is set"),
+		field.WithDefaultValue(ReportLookbackDaysDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gt(0)
+		}),
+	)
+	ReportStartDateField = field.StringField(
+		"report-start-date",
+		field.WithDescription("Include learning activity since this date (YYYY-MM-DD, not in the future) instead of using the lookback window"),
+		field.WithString(func(r *field.StringRuler) {
+			r.Pattern(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`)
+		}),
+	)
+	ReportSlicesField = field.IntField(
+		"report-slices",
+		field.WithDescription("Number of time slices a full learning activity report is split into and generated in parallel"),
+		field.WithDefaultValue(ReportSlicesDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gt(0)
+		}),
+	)
+	ReportConcurrencyField = field.IntField(
+		"report-concurrency",
+		field.WithDescription("Maximum number of learning activity report slices requested and polled at once"),
+		field.WithDefaultValue(ReportConcurrencyDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gt(0)
+		}),
+	)
+	RateLimitField = field.IntField(
+		"rate-limit",
+		field.WithDescription("Maximum average number of Percipio API requests per second, 0 disables client-side throttling"),
+		field.WithDefaultValue(RateLimitRequestsPerSecondDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gte(0)
+		}),
+	)
+	RateLimitBurstField = field.IntField(
+		"rate-limit-burst",
+		field.WithDescription("Maximum number of Percipio API requests sent in a burst before throttling applies"),
+		field.WithDefaultValue(RateLimitBurstDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gt(0)
+		}),
+	)
+	MaxRetriesField = field.IntField(
+		"max-retries",
+		field.WithDescription("Maximum number of times a Percipio API request answered with 429, or with 503 for GET, PUT and DELETE requests, is retried"),
+		field.WithDefaultValue(MaxRetriesDefault),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gte(0)
+		}),
+	)
+	ContentTypesField = field.StringSliceField(
+		"content-types",
+		field.WithDescription("Percipio content types to sync and report on: Course, Assessment, Book, Video, Audiobook (defaults to Course and Assessment)"),
+		field.WithStringSlice(func(r *field.StringSliceRuler) {
+			r.Unique(true)
+			r.ItemRules(func(item *field.StringRuler) {
+				item.In(ContentTypes)
+			})
+		}),
+	)
+	ReportAudiencesField = field.StringSliceField(
+		"report-audiences",
+		field.WithDescription("Limit the learning activity report to users in these Percipio audiences, by audience ID"),
+		field.WithStringSlice(func(r *field.StringSliceRuler) {
+			r.Unique(true)
+		}),
+	)
+	AttributeGroupsField = field.StringSliceField(
+		"attribute-groups",
+		field.WithDescription("Create groups from user values, as attribute:<custom attribute name> or report:<businessUnit|costCenterCode|deptName|division|geo>, report fields are not supported with incremental sync"),
+		field.WithStringSlice(func(r *field.StringSliceRuler) {
+			r.Unique(true)
+			r.ItemRules(func(item *field.StringRuler) {
+				item.Pattern(attributeGroupPattern)
+			})
+		}),
+	)
+	AssignmentDueDaysField = field.IntField(
+		"assignment-due-days",
+		field.WithDescription("Days after provisioning that course assignments created by the connector are due, 0 creates assignments without a due date"),
+		field.WithInt(func(r *field.IntRuler) {
+			r.Gte(0)
+		}),
+	)
+
+	DefaultRoleField = field.StringField(
+		"default-role",
+		field.WithDescription("Percipio role code given to users whose role is revoked and to new users created without a role"),
+		field.WithDefaultValue(DefaultRoleDefault),
+		field.WithString(func(r *field.StringRuler) {
+			r.Pattern(`^[A-Z][A-Z_]*$`)
+		}),
+	)
+
+	AllowUserDeletionField = field.BoolField(
+		"allow-user-deletion",
+		field.WithDescription("Allow the connector to delete Percipio users; Percipio refuses to delete users with recorded learning activity"),
+	)
+
 	LimitCoursesField = field.StringSliceField(
 		"limited-courses",
 		field.WithDescription("Limit imported courses to a specific list by Course ID"),
@@ -27,7 +209,33 @@ var (
 	// required.
 	ConfigurationFields = []field.SchemaField{
 		ApiTokenField,
+		PercipioClientIdField,
+		PercipioClientSecretField,
+		PercipioTokenUrlField,
 		OrganizationIdField,
+		RegionField,
+		BaseUrlField,
+		ReportPollInitialIntervalField,
+		ReportPollMaxIntervalField,
+		ReportPollTimeoutField,
+		ReportStateDirField,
+		GrantStoreField,
+		GrantStoreDirField,
+		IncrementalSyncField,
+		FullSyncIntervalField,
+		ReportLookbackField,
+		ReportStartDateField,
+		ReportSlicesField,
+		ReportConcurrencyField,
+		RateLimitField,
+		RateLimitBurstField,
+		MaxRetriesField,
+		ContentTypesField,
+		ReportAudiencesField,
+		AttributeGroupsField,
+		AssignmentDueDaysField,
+		DefaultRoleField,
+		AllowUserDeletionField,
 		LimitCoursesField,
 	}
 
@@ -35,10 +243,35 @@ var (
 	// ConfigurationFields that can be automatically validated. For example, a
 	// username and password can be required together, or an access token can be
 	// marked as mutually exclusive from the username password pair.
-	FieldRelationships = []field.SchemaFieldRelationship{}
+	FieldRelationships = []field.SchemaFieldRelationship{
+		field.FieldsAtLeastOneUsed(ApiTokenField, PercipioClientIdField),
+		field.FieldsMutuallyExclusive(ApiTokenField, PercipioClientIdField),
+		field.FieldsRequiredTogether(PercipioClientIdField, PercipioClientSecretField),
+		field.FieldsMutuallyExclusive(RegionField, BaseUrlField),
+	}
 
 	ConfigurationSchema = field.NewConfiguration(
 		ConfigurationFields,
 		field.WithConstraints(FieldRelationships...),
 	)
 )
+
+// ParseReportStartDate function parses the `report-start-date` configuration field.
+// It implements the validation the field schema cannot express, used by main before configuring the client.
+// The function parses the value with `ReportStartDateLayout` and rejects a date later than `now`.
+// Which turns a typo such as 2205-01-01 into a clear configuration error instead of an empty report.
+// This implementation returns the zero time for an empty value, so the `report-lookback-days` window applies.
+func ParseReportStartDate(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	startDate, err := time.Parse(ReportStartDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: expected a date in YYYY-MM-DD format: %w", ReportStartDateField.FieldName, value, err)
+	}
+	if startDate.After(now) {
+		return time.Time{}, fmt.Errorf("invalid %s %q: the date is in the future", ReportStartDateField.FieldName, value)
+	}
+	return startDate, nil
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/conductorone/baton-sdk/pkg/field"
 	"github.com/conductorone/baton-sdk/pkg/test"
@@ -41,7 +43,272 @@ func TestConfigs(t *testing.T) {
 			true,
 			"valid",
 		},
+		{
+			map[string]string{
+				"percipio-client-id":     "1",
+				"percipio-client-secret": "1",
+				"organization-id":        "1",
+			},
+			true,
+			"valid client credentials",
+		},
+		{
+			map[string]string{
+				"percipio-client-id": "1",
+				"organization-id":    "1",
+			},
+			false,
+			"missing client secret",
+		},
+		{
+			map[string]string{
+				"api-token":              "1",
+				"percipio-client-id":     "1",
+				"percipio-client-secret": "1",
+				"organization-id":        "1",
+			},
+			false,
+			"api token and client credentials",
+		},
+		{
+			map[string]string{
+				"api-token":       "1",
+				"organization-id": "1",
+				"percipio-region": "eu",
+			},
+			true,
+			"valid region",
+		},
+		{
+			map[string]string{
+				"api-token":       "1",
+				"organization-id": "1",
+				"percipio-region": "mars",
+			},
+			false,
+			"unknown region",
+		},
+		{
+			map[string]string{
+				"api-token":         "1",
+				"organization-id":   "1",
+				"percipio-base-url": "http://localhost:8080",
+			},
+			true,
+			"valid base url",
+		},
+		{
+			map[string]string{
+				"api-token":         "1",
+				"organization-id":   "1",
+				"percipio-base-url": "not a url",
+			},
+			false,
+			"invalid base url",
+		},
+		{
+			map[string]string{
+				"api-token":         "1",
+				"organization-id":   "1",
+				"percipio-region":   "ca",
+				"percipio-base-url": "http://localhost:8080",
+			},
+			false,
+			"region and base url",
+		},
+		{
+			map[string]string{
+				"api-token":         "1",
+				"organization-id":   "1",
+				"report-start-date": "2023-01-01",
+				"content-types":     "Video",
+			},
+			true,
+			"valid report scope",
+		},
+		{
+			map[string]string{
+				"api-token":        "1",
+				"organization-id":  "1",
+				"report-audiences": "audience-1,audience-2",
+			},
+			true,
+			"valid report audiences",
+		},
+		{
+			map[string]string{
+				"api-token":        "1",
+				"organization-id":  "1",
+				"attribute-groups": "attribute:Department report:deptName",
+			},
+			true,
+			"valid attribute groups",
+		},
+		{
+			map[string]string{
+				"api-token":        "1",
+				"organization-id":  "1",
+				"attribute-groups": "report:managerEmail",
+			},
+			false,
+			"unknown report group field",
+		},
+		{
+			map[string]string{
+				"api-token":           "1",
+				"organization-id":     "1",
+				"assignment-due-days": "14",
+			},
+			true,
+			"valid assignment due days",
+		},
+		{
+			map[string]string{
+				"api-token":           "1",
+				"organization-id":     "1",
+				"assignment-due-days": "-1",
+			},
+			false,
+			"negative assignment due days",
+		},
+		{
+			map[string]string{
+				"api-token":       "1",
+				"organization-id": "1",
+				"default-role":    "CONTENT_CURATOR",
+			},
+			true,
+			"valid default role",
+		},
+		{
+			map[string]string{
+				"api-token":       "1",
+				"organization-id": "1",
+				"default-role":    "learner",
+			},
+			false,
+			"invalid default role",
+		},
+		{
+			map[string]string{
+				"api-token":           "1",
+				"organization-id":     "1",
+				"allow-user-deletion": "true",
+			},
+			true,
+			"user deletion allowed",
+		},
+		{
+			map[string]string{
+				"api-token":         "1",
+				"organization-id":   "1",
+				"report-start-date": "01/01/2023",
+			},
+			false,
+			"invalid report start date",
+		},
+		{
+			map[string]string{
+				"api-token":       "1",
+				"organization-id": "1",
+				"content-types":   "Podcast",
+			},
+			false,
+			"unknown content type",
+		},
+		{
+			map[string]string{
+				"api-token":          "1",
+				"organization-id":    "1",
+				"report-slices":      "8",
+				"report-concurrency": "4",
+			},
+			true,
+			"valid report slicing",
+		},
+		{
+			map[string]string{
+				"api-token":       "1",
+				"organization-id": "1",
+				"report-slices":   "-1",
+			},
+			false,
+			"negative report slices",
+		},
+		{
+			map[string]string{
+				"api-token":        "1",
+				"organization-id":  "1",
+				"rate-limit":       "5",
+				"rate-limit-burst": "20",
+				"max-retries":      "5",
+			},
+			true,
+			"valid rate limit",
+		},
+		{
+			map[string]string{
+				"api-token":       "1",
+				"organization-id": "1",
+				"max-retries":     "-1",
+			},
+			false,
+			"negative max retries",
+		},
 	}
 
 	test.ExerciseTestCases(t, configurationSchema, nil, testCases)
 }
+
+func TestParseReportStartDate(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	for _, testCase := range []struct {
+		name          string
+		value         string
+		expected      time.Time
+		expectedError string
+	}{
+		{
+			name:     "empty",
+			value:    "",
+			expected: time.Time{},
+		},
+		{
+			name:     "past date",
+			value:    "2023-01-01",
+			expected: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "today",
+			value:    "2024-06-15",
+			expected: time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "future date",
+			value:         "2024-06-16",
+			expectedError: "the date is in the future",
+		},
+		{
+			name:          "impossible date",
+			value:         "2024-02-30",
+			expectedError: "expected a date in YYYY-MM-DD format",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			startDate, err := ParseReportStartDate(testCase.value, now)
+			if testCase.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", testCase.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !startDate.Equal(testCase.expected) {
+				t.Fatalf("expected %s, got %s", testCase.expected, startDate)
+			}
+		})
+	}
+}
@@ -1,6 +1,71 @@
 package config
 
+import "strings"
+
+const (
+	ReportPollInitialIntervalSecondsDefault = 5
+	ReportPollMaxIntervalSecondsDefault     = 60
+	ReportPollTimeoutMinutesDefault         = 300
+	FullSyncIntervalHoursDefault            = 168
+	ReportLookbackDaysDefault               = 3650
+	ReportSlicesDefault                     = 1
+	ReportConcurrencyDefault                = 4
+	RateLimitRequestsPerSecondDefault       = 10
+	RateLimitBurstDefault                   = 10
+	MaxRetriesDefault                       = 3
+	ReportStartDateLayout                   = "2006-01-02"
+	DefaultRoleDefault                      = "LEARNER"
+	AdminRole                               = "ADMIN"
+)
+
+const (
+	RegionUS = "us"
+	RegionEU = "eu"
+	RegionCA = "ca"
+)
+
+var Regions = []string{RegionUS, RegionEU, RegionCA}
+
+const (
+	GrantStoreMemory = "memory"
+	GrantStoreDisk   = "disk"
+)
+
+var GrantStores = []string{GrantStoreMemory, GrantStoreDisk}
+
+const (
+	ContentTypeCourse     = "Course"
+	ContentTypeAssessment = "Assessment"
+	ContentTypeBook       = "Book"
+	ContentTypeVideo      = "Video"
+	ContentTypeAudiobook  = "Audiobook"
+)
+
+var (
+	ContentTypes        = []string{ContentTypeCourse, ContentTypeAssessment, ContentTypeBook, ContentTypeVideo, ContentTypeAudiobook}
+	ContentTypesDefault = []string{ContentTypeCourse, ContentTypeAssessment}
+)
+
 const (
-	RetryAttemptsMaximum = 300
-	RetryAfterSeconds    = 60
+	AttributeGroupSourceAttribute = "attribute"
+	AttributeGroupSourceReport    = "report"
 )
+
+const (
+	ReportGroupFieldBusinessUnit   = "businessUnit"
+	ReportGroupFieldCostCenterCode = "costCenterCode"
+	ReportGroupFieldDeptName       = "deptName"
+	ReportGroupFieldDivision       = "division"
+	ReportGroupFieldGeo            = "geo"
+)
+
+var ReportGroupFields = []string{
+	ReportGroupFieldBusinessUnit,
+	ReportGroupFieldCostCenterCode,
+	ReportGroupFieldDeptName,
+	ReportGroupFieldDivision,
+	ReportGroupFieldGeo,
+}
+
+var attributeGroupPattern = "^(" + AttributeGroupSourceAttribute + ":.+|" +
+	AttributeGroupSourceReport + ":(" + strings.Join(ReportGroupFields, "|") + "))$"
@@ -0,0 +1,220 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	configv1 "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	actionDisableUser    = "disable_user"
+	actionEnableUser     = "enable_user"
+	actionArgumentUserId = "user_id"
+	actionResultSuccess  = "success"
+	actionResultChanged  = "changed"
+	actionResultResource = "resource"
+	actionIdSeparator    = ":"
+)
+
+// userActionSchemas lists the custom actions the connector offers for users.
+// It is used by `ListActionSchemas`, `GetActionSchema` and `InvokeAction` to describe and dispatch actions.
+// It holds one schema per action name, each taking the user ID and returning the updated user resource.
+// This variable keeps the action definitions in one place so that listing and invoking them cannot drift apart.
+// The instance is a fixed lookup table and is never mutated.
+var userActionSchemas = map[string]*v2.BatonActionSchema{
+	actionDisableUser: userActionSchema(
+		actionDisableUser,
+		"Disable user",
+		"Deactivates a Percipio user, releasing their license seat.",
+	),
+	actionEnableUser: userActionSchema(
+		actionEnableUser,
+		"Enable user",
+		"Reactivates a previously deactivated Percipio user.",
+	),
+}
+
+// userActionSchema function builds the schema of a custom action that targets one user.
+// It implements the shared shape of the `disable_user` and `enable_user` actions.
+// The function declares a required `user_id` argument and the `success`, `changed` and `resource` results.
+// Which lets workflows pass a user ID from a synced resource and read back the updated user.
+// This implementation only varies the name, display name and description between actions.
+func userActionSchema(name string, displayName string, description string) *v2.BatonActionSchema {
+	return &v2.BatonActionSchema{
+		Name:        name,
+		DisplayName: displayName,
+		Description: description,
+		Arguments: []*configv1.Field{
+			{
+				Name:        actionArgumentUserId,
+				DisplayName: "User ID",
+				Description: "The Percipio ID of the user",
+				IsRequired:  true,
+				Field:       &configv1.Field_StringField{StringField: &configv1.StringField{}},
+			},
+		},
+		ReturnTypes: []*configv1.Field{
+			{
+				Name:        actionResultSuccess,
+				DisplayName: "Success",
+				Field:       &configv1.Field_BoolField{BoolField: &configv1.BoolField{}},
+			},
+			{
+				Name:        actionResultChanged,
+				DisplayName: "Changed",
+				Description: "Whether the user was updated, false when it was already in the requested state",
+				Field:       &configv1.Field_BoolField{BoolField: &configv1.BoolField{}},
+			},
+			{
+				Name:        actionResultResource,
+				DisplayName: "Resource",
+				Description: "The updated user resource",
+				Field:       &configv1.Field_StringMapField{StringMapField: &configv1.StringMapField{}},
+			},
+		},
+	}
+}
+
+// ListActionSchemas method returns the custom actions offered by the connector.
+// It implements the `ListActionSchemas` method required by the `connectorbuilder.CustomActionManager` interface.
+// The method returns the schemas of the `disable_user` and `enable_user` actions.
+// Which lets offboarding and onboarding workflows change a user's status in Percipio.
+// This implementation returns the actions in a stable order.
+func (d *Connector) ListActionSchemas(_ context.Context) ([]*v2.BatonActionSchema, annotations.Annotations, error) {
+	return []*v2.BatonActionSchema{
+		userActionSchemas[actionDisableUser],
+		userActionSchemas[actionEnableUser],
+	}, nil, nil
+}
+
+// GetActionSchema method returns the schema of a single custom action.
+// It implements the `GetActionSchema` method required by the `connectorbuilder.CustomActionManager` interface.
+// The method looks the action up by name in `userActionSchemas`.
+// Which lets the Baton application render the action's arguments before invoking it.
+// This implementation returns a `NotFound` error for unknown actions.
+func (d *Connector) GetActionSchema(_ context.Context, name string) (*v2.BatonActionSchema, annotations.Annotations, error) {
+	schema, ok := userActionSchemas[name]
+	if !ok {
+		return nil, nil, status.Errorf(codes.NotFound, "baton-percipio: unknown action %s", name)
+	}
+	return schema, nil, nil
+}
+
+// InvokeAction method runs a custom action.
+// It implements the `InvokeAction` method required by the `connectorbuilder.CustomActionManager` interface.
+// The method sets the user's active status through the user-management API and returns the updated user resource.
+// Which frees license seats on offboarding and restores access on rehire without a Percipio admin.
+// This implementation is idempotent: a user already in the requested state is returned unchanged without an update,
+// reading the state past the HTTP cache so an earlier action in the same process is seen, and the action completes before the method returns.
+// Disabling the organization's last active admin is refused with a `FailedPrecondition` error, like demoting it through the role entitlement.
+func (d *Connector) InvokeAction(
+	ctx context.Context,
+	name string,
+	args *structpb.Struct,
+) (
+	string,
+	v2.BatonActionStatus,
+	*structpb.Struct,
+	annotations.Annotations,
+	error,
+) {
+	if _, ok := userActionSchemas[name]; !ok {
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, status.Errorf(codes.NotFound, "baton-percipio: unknown action %s", name)
+	}
+
+	userId := args.GetFields()[actionArgumentUserId].GetStringValue()
+	if userId == "" {
+		return "", v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, nil, status.Errorf(codes.InvalidArgument, "baton-percipio: %s is required", actionArgumentUserId)
+	}
+	actionId := name + actionIdSeparator + userId
+
+	var outputAnnotations annotations.Annotations
+	user, ratelimitData, err := d.client.GetUser(client.Uncached(ctx), userId)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return actionId, v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, outputAnnotations, err
+	}
+
+	active := name == actionEnableUser
+	changed := user.IsActive != active
+	if changed && !active {
+		guardAnnotations, err := checkNotLastAdmin(ctx, d.client, user)
+		if err != nil {
+			return actionId, v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, guardAnnotations, err
+		}
+	}
+	if changed {
+		user, ratelimitData, err = d.client.UpdateUser(ctx, userId, client.UserPatch{IsActive: &active})
+		outputAnnotations.WithRateLimiting(ratelimitData)
+		if err != nil {
+			return actionId, v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, outputAnnotations, err
+		}
+	}
+
+	response, err := userActionResponse(*user, changed, organizationResourceID(d.client.OrganizationId()))
+	if err != nil {
+		return actionId, v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, nil, outputAnnotations, err
+	}
+	return actionId, v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, response, outputAnnotations, nil
+}
+
+// userActionResponse function builds the result of a user action.
+// It implements the response shared by the `disable_user` and `enable_user` actions.
+// The function converts the user into a resource with `userResource` and embeds it, as JSON, next to the `success` and `changed` flags.
+// Which returns the same resource representation a sync would produce.
+// This implementation round-trips the resource through protojson, since action results are plain structs.
+func userActionResponse(user client.User, changed bool, parentResourceID *v2.ResourceId) (*structpb.Struct, error) {
+	resource, err := userResource(user, parentResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceJson, err := protojson.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	resourceStruct := &structpb.Struct{}
+	err = protojson.Unmarshal(resourceJson, resourceStruct)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode user resource: %w", err)
+	}
+
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			actionResultSuccess:  structpb.NewBoolValue(true),
+			actionResultChanged:  structpb.NewBoolValue(changed),
+			actionResultResource: structpb.NewStructValue(resourceStruct),
+		},
+	}, nil
+}
+
+// GetActionStatus method returns the status of a previously invoked custom action.
+// It implements the `GetActionStatus` method required by the `connectorbuilder.CustomActionManager` interface.
+// The method parses the action name from the ID returned by `InvokeAction`.
+// Which satisfies callers that poll for completion even though every action completes synchronously.
+// This implementation reports known actions as complete and returns a `NotFound` error otherwise.
+func (d *Connector) GetActionStatus(
+	_ context.Context,
+	id string,
+) (
+	v2.BatonActionStatus,
+	string,
+	*structpb.Struct,
+	annotations.Annotations,
+	error,
+) {
+	name, userId, ok := strings.Cut(id, actionIdSeparator)
+	if _, known := userActionSchemas[name]; ok && known && userId != "" {
+		return v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, name, nil, nil, nil
+	}
+	return v2.BatonActionStatus_BATON_ACTION_STATUS_UNKNOWN, "", nil, nil, status.Errorf(codes.NotFound, "baton-percipio: unknown action id %s", id)
+}
@@ -0,0 +1,162 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestUserActions(t *testing.T) {
+	ctx := context.Background()
+
+	newUsersServer := func(users []*client.User, requests *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			*requests = append(*requests, request.Method+" "+request.URL.Path)
+			writer.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(request.URL.Path, "/users") {
+				writer.Header().Set(client.HeaderNameTotalCount, strconv.Itoa(len(users)))
+				_ = json.NewEncoder(writer).Encode(users)
+				return
+			}
+			for _, user := range users {
+				if !strings.HasSuffix(request.URL.Path, "/users/"+user.Id) {
+					continue
+				}
+				if request.Method == http.MethodPatch {
+					var patch client.UserPatch
+					_ = json.NewDecoder(request.Body).Decode(&patch)
+					if patch.IsActive != nil {
+						user.IsActive = *patch.IsActive
+					}
+				}
+				_ = json.NewEncoder(writer).Encode(user)
+				return
+			}
+			writer.WriteHeader(http.StatusNotFound)
+		}))
+	}
+
+	newConnector := func(t *testing.T, serverUrl string) *Connector {
+		percipioClient, err := client.New(ctx, serverUrl, "mock", "token")
+		require.Nil(t, err)
+		return &Connector{client: percipioClient}
+	}
+
+	userArgs := func(t *testing.T, userId string) *structpb.Struct {
+		args, err := structpb.NewStruct(map[string]interface{}{"user_id": userId})
+		require.Nil(t, err)
+		return args
+	}
+
+	t.Run("should list the user actions", func(t *testing.T) {
+		schemas, _, err := (&Connector{}).ListActionSchemas(ctx)
+		require.Nil(t, err)
+		require.Len(t, schemas, 2)
+		require.Equal(t, "disable_user", schemas[0].Name)
+		require.Equal(t, "enable_user", schemas[1].Name)
+
+		_, _, err = (&Connector{}).GetActionSchema(ctx, "delete_everything")
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("should disable an active user", func(t *testing.T) {
+		var requests []string
+		user := &client.User{Id: "00000000-0000-0000-0000-000000000001", FirstName: "Jane", IsActive: true}
+		server := newUsersServer([]*client.User{user}, &requests)
+		defer server.Close()
+
+		id, actionStatus, response, _, err := newConnector(t, server.URL).InvokeAction(ctx, "disable_user", userArgs(t, user.Id))
+		require.Nil(t, err)
+		require.Equal(t, v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, actionStatus)
+		require.Equal(t, []string{
+			"GET /user-management/v1/organizations/mock/users/00000000-0000-0000-0000-000000000001",
+			"PATCH /user-management/v1/organizations/mock/users/00000000-0000-0000-0000-000000000001",
+		}, requests)
+		require.False(t, user.IsActive)
+		require.True(t, response.Fields["success"].GetBoolValue())
+		require.True(t, response.Fields["changed"].GetBoolValue())
+		require.Equal(t, user.Id, response.Fields["resource"].GetStructValue().Fields["id"].GetStructValue().Fields["resource"].GetStringValue())
+
+		statusAgain, name, _, _, err := newConnector(t, server.URL).GetActionStatus(ctx, id)
+		require.Nil(t, err)
+		require.Equal(t, v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, statusAgain)
+		require.Equal(t, "disable_user", name)
+	})
+
+	t.Run("should not update a user already in the requested state", func(t *testing.T) {
+		var requests []string
+		user := &client.User{Id: "00000000-0000-0000-0000-000000000002", IsActive: true}
+		server := newUsersServer([]*client.User{user}, &requests)
+		defer server.Close()
+
+		_, actionStatus, response, _, err := newConnector(t, server.URL).InvokeAction(ctx, "enable_user", userArgs(t, user.Id))
+		require.Nil(t, err)
+		require.Equal(t, v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, actionStatus)
+		require.Len(t, requests, 1)
+		require.True(t, response.Fields["success"].GetBoolValue())
+		require.False(t, response.Fields["changed"].GetBoolValue())
+	})
+
+	t.Run("should read the current state of a user changed earlier", func(t *testing.T) {
+		var requests []string
+		user := &client.User{Id: "00000000-0000-0000-0000-000000000003", IsActive: true}
+		server := newUsersServer([]*client.User{user}, &requests)
+		defer server.Close()
+
+		connector := newConnector(t, server.URL)
+		_, _, response, _, err := connector.InvokeAction(ctx, "disable_user", userArgs(t, user.Id))
+		require.Nil(t, err)
+		require.True(t, response.Fields["changed"].GetBoolValue())
+		require.False(t, user.IsActive)
+
+		_, _, response, _, err = connector.InvokeAction(ctx, "enable_user", userArgs(t, user.Id))
+		require.Nil(t, err)
+		require.True(t, response.Fields["changed"].GetBoolValue())
+		require.True(t, user.IsActive)
+		require.Len(t, requests, 4)
+	})
+
+	t.Run("should refuse to disable the last active admin", func(t *testing.T) {
+		var requests []string
+		admin := &client.User{Id: "00000000-0000-0000-0000-000000000004", Role: "ADMIN", IsActive: true}
+		inactiveAdmin := &client.User{Id: "00000000-0000-0000-0000-000000000005", Role: "ADMIN", IsActive: false}
+		server := newUsersServer([]*client.User{admin, inactiveAdmin}, &requests)
+		defer server.Close()
+
+		_, actionStatus, _, _, err := newConnector(t, server.URL).InvokeAction(ctx, "disable_user", userArgs(t, admin.Id))
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.Equal(t, v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED, actionStatus)
+		require.True(t, admin.IsActive)
+		require.NotContains(t, requests, "PATCH /user-management/v1/organizations/mock/users/"+admin.Id)
+	})
+
+	t.Run("should disable an admin when another active admin remains", func(t *testing.T) {
+		var requests []string
+		admin := &client.User{Id: "00000000-0000-0000-0000-000000000004", Role: "ADMIN", IsActive: true}
+		otherAdmin := &client.User{Id: "00000000-0000-0000-0000-000000000005", Role: "ADMIN", IsActive: true}
+		server := newUsersServer([]*client.User{admin, otherAdmin}, &requests)
+		defer server.Close()
+
+		_, actionStatus, _, _, err := newConnector(t, server.URL).InvokeAction(ctx, "disable_user", userArgs(t, admin.Id))
+		require.Nil(t, err)
+		require.Equal(t, v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE, actionStatus)
+		require.False(t, admin.IsActive)
+		require.True(t, otherAdmin.IsActive)
+	})
+
+	t.Run("should require a user ID", func(t *testing.T) {
+		_, _, _, _, err := (&Connector{}).InvokeAction(ctx, "enable_user", &structpb.Struct{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
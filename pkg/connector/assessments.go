@@ -0,0 +1,153 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const (
+	passedEntitlement = "passed"
+	failedEntitlement = "failed"
+)
+
+// assessmentEntitlements lists the outcome entitlements offered by every assessment.
+// It is used by the assessment builder to declare entitlements and to filter the statuses it grants.
+// It holds the entitlement slug and a past-tense description for each assessment outcome.
+// This variable keeps the entitlement list and the grant filter in sync.
+// The instance is a fixed lookup table and is never mutated.
+var assessmentEntitlements = []struct {
+	slug        string
+	description string
+}{
+	{passedEntitlement, "Passed"},
+	{failedEntitlement, "Failed"},
+	{inProgressEntitlement, "In progress"},
+}
+
+// assessmentBuilder struct is responsible for syncing assessment resources and their associated grants.
+// It is used by the connector to fetch and process all assessment data from the Percipio API.
+// It holds a reference to the API client, the assessment resource type descriptor, and the catalog shared with the other content builders.
+// This structure organizes the context needed for all assessment-related synchronization operations.
+// Instances are created by the `newAssessmentBuilder` function.
+type assessmentBuilder struct {
+	client       *client.Client
+	resourceType *v2.ResourceType
+	catalog      *contentCatalog
+}
+
+// ResourceType method returns the resource type descriptor for assessments.
+// It implements the `ResourceType` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method returns the static `assessmentResourceType` object defined for this connector.
+// Which informs the baton-sdk about the type of resource this syncer is responsible for.
+// This implementation returns a pre-defined object.
+func (o *assessmentBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// List method fetches a page of assessments and returns them as `v2.Resource` objects.
+// It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method reads a page of the shared catalog and keeps the items whose `percipioType` is ASSESSMENT.
+// Which enables the baton-sdk to paginate through all assessment resources in the upstream system.
+// This implementation delegates to `listContent`, shared with the course builder.
+func (o *assessmentBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return listContent(ctx, o.catalog, o.resourceType, parentResourceID, pToken)
+}
+
+// Entitlements method returns the entitlements for an assessment resource.
+// It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method defines the 'passed', 'failed', and 'in_progress' entitlements for a given assessment.
+// Which allows Baton to model the outcome of a user's attempts at an assessment.
+// This implementation returns a static list of three assignment entitlements.
+func (o *assessmentBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	entitlements := make([]*v2.Entitlement, 0, len(assessmentEntitlements))
+	for _, outcome := range assessmentEntitlements {
+		entitlements = append(entitlements, entitlement.NewAssignmentEntitlement(
+			resource,
+			outcome.slug,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("Assessment %s %s", resource.DisplayName, outcome.slug)),
+			entitlement.WithDescription(fmt.Sprintf("%s assessment %s in Percipio", outcome.description, resource.DisplayName)),
+		))
+	}
+	return entitlements, "", nil, nil
+}
+
+// Grants method fetches and returns the grants for an assessment resource.
+// It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method loads the learning activity report, shared with the course builder, and grants each user the entitlement matching their assessment outcome.
+// Which is the only mechanism for determining assessment results in the Percipio API.
+// This implementation skips report rows whose status could not be mapped to an assessment outcome.
+func (o *assessmentBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	var outputAnnotations annotations.Annotations
+	err := loadLearningActivityReport(ctx, o.client, &outputAnnotations)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	statusesMap, err := o.client.StatusesStore.Get(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for userId, status := range statusesMap {
+		if status != client.StatusPassed && status != client.StatusFailed && status != client.StatusInProgress {
+			continue
+		}
+		principalId, err := resourceSdk.NewResourceID(userResourceType, userId)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		grants = append(grants, grant.NewGrant(resource, status, principalId))
+	}
+
+	return grants, "", outputAnnotations, nil
+}
+
+// newAssessmentBuilder function creates a new `assessmentBuilder`.
+// It implements the constructor for the assessment resource syncer.
+// The function initializes an `assessmentBuilder` with an API client, the assessment resource type, and the shared content catalog.
+// Which provides a configured syncer ready to be used by the main connector.
+// This implementation sets up the builder with its required dependencies.
+func newAssessmentBuilder(client *client.Client, catalog *contentCatalog) *assessmentBuilder {
+	return &assessmentBuilder{
+		client:       client,
+		resourceType: assessmentResourceType,
+		catalog:      catalog,
+	}
+}
@@ -0,0 +1,80 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-percipio/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssessments(t *testing.T) {
+	ctx := context.Background()
+	server := test.FixturesServer()
+	defer server.Close()
+
+	percipioClient, err := client.New(
+		ctx,
+		server.URL,
+		"mock",
+		"token",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should only list assessments", func(t *testing.T) {
+		a := newAssessmentBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		resources, _, listAnnotations, err := a.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, listAnnotations)
+		require.Len(t, resources, 1)
+		require.Equal(t, assessmentResourceType.Id, resources[0].Id.ResourceType)
+		require.Equal(t, "00000000-0000-0000-0000-0000000000a1", resources[0].Id.Resource)
+
+		c := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		courses, _, _, err := c.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		for _, course := range courses {
+			require.Equal(t, courseResourceType.Id, course.Id.ResourceType)
+		}
+	})
+
+	t.Run("should grant assessment outcomes", func(t *testing.T) {
+		a := newAssessmentBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		assessment, err := courseResource(ctx, client.Course{
+			Id: "00000000-0000-0000-0000-0000000000a1",
+			ContentType: client.ContentType{
+				PercipioType: "ASSESSMENT",
+			},
+		}, nil, a.catalog.contentTypes)
+		require.Nil(t, err)
+
+		grants, _, _, err := a.Grants(ctx, assessment, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 2)
+
+		outcomes := make(map[string]string)
+		for _, assessmentGrant := range grants {
+			outcomes[assessmentGrant.Principal.Id.Resource] = assessmentGrant.Entitlement.Id
+		}
+		require.Equal(t, map[string]string{
+			"00000000-0000-0000-0000-000000000001": entitlement.NewEntitlementID(assessment, failedEntitlement),
+			"00000000-0000-0000-0000-000000000002": entitlement.NewEntitlementID(assessment, passedEntitlement),
+		}, outcomes)
+	})
+
+	t.Run("should declare outcome entitlements", func(t *testing.T) {
+		a := newAssessmentBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		entitlements, _, _, err := a.Entitlements(ctx, &v2.Resource{
+			Id:          &v2.ResourceId{ResourceType: assessmentResourceType.Id, Resource: "a1"},
+			DisplayName: "Security Awareness Assessment",
+		}, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 3)
+	})
+}
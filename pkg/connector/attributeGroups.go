@@ -0,0 +1,295 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+// attributeGroup struct records one synthesized group and its members.
+// It is used by the attribute group builder's index to list groups and their grants.
+// It holds the mapping the group was derived from, the shared value, and the IDs of the users holding it.
+// This structure organizes membership computed from user data rather than fetched from Percipio.
+// Instances are created by `buildIndex` while reading users and report rows.
+type attributeGroup struct {
+	mapping client.AttributeGroupMapping
+	value   string
+	members mapset.Set[string]
+}
+
+// attributeGroupBuilder struct is responsible for syncing groups synthesized from user attribute values.
+// It is used by the connector when `attribute-groups` is configured, e.g. to group users by department.
+// It holds a reference to the API client, the attribute group resource type descriptor, the shared user directory, and the group index.
+// This structure organizes the context needed for all attribute group synchronization operations.
+// Instances are created by the `newAttributeGroupBuilder` function.
+type attributeGroupBuilder struct {
+	client       *client.Client
+	resourceType *v2.ResourceType
+	users        *userDirectory
+
+	mu       sync.Mutex
+	groups   map[string]*attributeGroup
+	groupIds []string
+}
+
+// ResourceType method returns the resource type descriptor for attribute groups.
+// It implements the `ResourceType` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method returns the static `attributeGroupResourceType` object defined for this connector.
+// Which informs the baton-sdk about the type of resource this syncer is responsible for.
+// This implementation returns a pre-defined object.
+func (o *attributeGroupBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// attributeGroupId function builds the resource ID of a synthesized group.
+// It implements the stable identity of attribute groups across syncs.
+// The function combines the mapping's source and name with the shared value.
+// Which keeps groups with the same value from different attributes apart.
+// This implementation uses the raw value, so renaming a department creates a new group.
+func attributeGroupId(mapping client.AttributeGroupMapping, value string) string {
+	return fmt.Sprintf("%s:%s:%s", mapping.Source, mapping.Name, value)
+}
+
+// attributeGroupResource function creates a new `v2.Resource` for a synthesized group.
+// It implements the mapping from an attribute value to the baton-sdk's resource model.
+// The function names the group after the attribute and value and records both in the group trait's profile.
+// Which lets reviewers recognize e.g. "deptName: Engineering" at a glance.
+// This implementation uses the `resourceSdk.NewGroupResource` helper to construct a resource with the `GroupTrait`.
+func attributeGroupResource(group *attributeGroup, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	return resourceSdk.NewGroupResource(
+		fmt.Sprintf("%s: %s", group.mapping.Name, group.value),
+		attributeGroupResourceType,
+		attributeGroupId(group.mapping, group.value),
+		[]resourceSdk.GroupTraitOption{
+			resourceSdk.WithGroupProfile(map[string]interface{}{
+				"source":    group.mapping.Source,
+				"attribute": group.mapping.Name,
+				"value":     group.value,
+			}),
+		},
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+// reset method drops the group index.
+// It implements the `syncCache` interface used by the connector's `Cleanup`.
+// The method clears the index so the next call to `buildIndex` groups the users again.
+// Which keeps synthesized groups in step with the user directory once it is reset too.
+// This implementation keeps the client and the user directory.
+func (o *attributeGroupBuilder) reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.groups = nil
+	o.groupIds = nil
+}
+
+// buildIndex method computes every synthesized group and its members once.
+// It implements the group discovery shared by `List` and `Grants`.
+// The method reads custom attributes from the shared user directory and, for report mappings,
+// the organization fields collected while loading the learning activity report.
+// Which turns scattered user values into a fixed set of groups for the whole sync.
+// This implementation only loads the report when a report field is configured, and ignores empty values.
+func (o *attributeGroupBuilder) buildIndex(ctx context.Context) (annotations.Annotations, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var outputAnnotations annotations.Annotations
+	if o.groupIds != nil {
+		return outputAnnotations, nil
+	}
+
+	groups := make(map[string]*attributeGroup)
+	addMember := func(mapping client.AttributeGroupMapping, value string, userId string) {
+		if value == "" {
+			return
+		}
+		groupId := attributeGroupId(mapping, value)
+		group, ok := groups[groupId]
+		if !ok {
+			group = &attributeGroup{
+				mapping: mapping,
+				value:   value,
+				members: mapset.NewThreadUnsafeSet[string](),
+			}
+			groups[groupId] = group
+		}
+		group.members.Add(userId)
+	}
+
+	var users []client.User
+	var reportFields map[string]map[string]string
+	for _, mapping := range o.client.AttributeGroups() {
+		switch mapping.Source {
+		case config.AttributeGroupSourceAttribute:
+			if users == nil {
+				var err error
+				users, outputAnnotations, err = o.users.all(ctx)
+				if err != nil {
+					return outputAnnotations, err
+				}
+			}
+			for _, user := range users {
+				for _, attribute := range user.CustomAttributes {
+					if attribute.Name == mapping.Name {
+						addMember(mapping, attribute.Value, user.Id)
+					}
+				}
+			}
+		case config.AttributeGroupSourceReport:
+			if reportFields == nil {
+				err := loadLearningActivityReport(ctx, o.client, &outputAnnotations)
+				if err != nil {
+					return outputAnnotations, err
+				}
+				reportFields = o.client.ReportUserFields()
+			}
+			for userId, values := range reportFields {
+				addMember(mapping, values[mapping.Name], userId)
+			}
+		}
+	}
+
+	groupIds := make([]string, 0, len(groups))
+	for groupId := range groups {
+		groupIds = append(groupIds, groupId)
+	}
+	slices.Sort(groupIds)
+
+	o.groups = groups
+	o.groupIds = groupIds
+	return outputAnnotations, nil
+}
+
+// List method returns a page of synthesized groups as `v2.Resource` objects.
+// It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method builds the group index on first use and returns the requested slice of groups, sorted by ID.
+// Which enables the baton-sdk to sync one group per distinct configured value.
+// This implementation uses the offset-based `client.ParseUserPaginationToken` and `client.GetUserNextToken` helpers over the in-memory index,
+// and only lists groups under the organization.
+func (o *attributeGroupBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	outputAnnotations, err := o.buildIndex(ctx)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	offset, limit, err := client.ParseUserPaginationToken(pToken)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	start := min(offset, len(o.groupIds))
+	end := min(offset+limit, len(o.groupIds))
+	outputResources := make([]*v2.Resource, 0, end-start)
+	for _, groupId := range o.groupIds[start:end] {
+		resource, err := attributeGroupResource(o.groups[groupId], parentResourceID)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		outputResources = append(outputResources, resource)
+	}
+
+	return outputResources, client.GetUserNextToken(ctx, offset, limit, len(o.groupIds)), outputAnnotations, nil
+}
+
+// Entitlements method returns the entitlements for an attribute group resource.
+// It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method defines the 'member' entitlement for a given group.
+// Which lets reviewers filter course completion and other grants by the users holding a value.
+// This implementation returns a static list of one assignment entitlement.
+func (o *attributeGroupBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			memberEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, memberEntitlement)),
+			entitlement.WithDescription(fmt.Sprintf("Has %s in Percipio", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// Grants method returns a membership grant for every user holding a group's value.
+// It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method looks the group up in the index and grants 'member' to each of its users.
+// Which turns user attributes into reviewable group membership.
+// This implementation returns no grants for a group that is no longer in the index.
+func (o *attributeGroupBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	outputAnnotations, err := o.buildIndex(ctx)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	group, ok := o.groups[resource.Id.Resource]
+	if !ok {
+		return nil, "", outputAnnotations, nil
+	}
+
+	members := group.members.ToSlice()
+	slices.Sort(members)
+	grants := make([]*v2.Grant, 0, len(members))
+	for _, userId := range members {
+		principalId, err := resourceSdk.NewResourceID(userResourceType, userId)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		grants = append(grants, grant.NewGrant(resource, memberEntitlement, principalId))
+	}
+
+	return grants, "", outputAnnotations, nil
+}
+
+// newAttributeGroupBuilder function creates a new `attributeGroupBuilder`.
+// It implements the constructor for the attribute group resource syncer.
+// The function initializes an `attributeGroupBuilder` with an API client, the attribute group resource type, and the shared user directory.
+// Which provides a configured syncer ready to be used by the main connector.
+// This implementation leaves the group index empty until it is first needed.
+func newAttributeGroupBuilder(client *client.Client, users *userDirectory) *attributeGroupBuilder {
+	return &attributeGroupBuilder{
+		client:       client,
+		resourceType: attributeGroupResourceType,
+		users:        users,
+	}
+}
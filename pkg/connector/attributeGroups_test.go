@@ -0,0 +1,97 @@
+package connector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-percipio/test"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeGroups(t *testing.T) {
+	ctx := context.Background()
+	server := test.FixturesServer()
+	defer server.Close()
+
+	percipioClient, err := client.New(
+		ctx,
+		server.URL,
+		"mock",
+		"token",
+		client.WithAttributeGroups([]string{
+			"attribute:Country Name",
+			"report:deptName",
+			"report:unknownField",
+			"department",
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should parse valid mappings only", func(t *testing.T) {
+		require.Equal(t, []client.AttributeGroupMapping{
+			{Source: "attribute", Name: "Country Name"},
+			{Source: "report", Name: "deptName"},
+		}, percipioClient.AttributeGroups())
+	})
+
+	t.Run("should synthesize groups and memberships", func(t *testing.T) {
+		g := newAttributeGroupBuilder(percipioClient, newUserDirectory(percipioClient))
+		resources, nextToken, listAnnotations, err := g.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, listAnnotations)
+		require.Empty(t, nextToken)
+		require.Len(t, resources, 2)
+
+		members := make(map[string][]string)
+		for _, resource := range resources {
+			require.Equal(t, attributeGroupResourceType.Id, resource.Id.ResourceType)
+			grants, _, _, err := g.Grants(ctx, resource, &pagination.Token{})
+			require.Nil(t, err)
+			for _, groupGrant := range grants {
+				members[resource.DisplayName] = append(members[resource.DisplayName], groupGrant.Principal.Id.Resource)
+			}
+		}
+		require.Equal(t, map[string][]string{
+			"Country Name: IND": {"00000000-0000-0000-0000-000000000001"},
+			"deptName: ZPA Ops": {
+				"00000000-0000-0000-0000-000000000001",
+				"00000000-0000-0000-0000-000000000002",
+			},
+		}, members)
+	})
+
+	t.Run("should not sync attribute groups unless configured", func(t *testing.T) {
+		unconfigured, err := New(ctx, "", server.URL, "mock", "token", nil)
+		require.Nil(t, err)
+		for _, syncer := range unconfigured.ResourceSyncers(ctx) {
+			require.NotEqual(t, attributeGroupResourceType.Id, syncer.ResourceType(ctx).Id)
+		}
+	})
+
+	t.Run("should refuse report groups with incremental sync", func(t *testing.T) {
+		_, err := client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			client.WithAttributeGroups([]string{"report:deptName"}),
+			client.WithIncrementalSync(24*time.Hour),
+		)
+		require.ErrorContains(t, err, "incremental sync")
+
+		_, err = client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			client.WithAttributeGroups([]string{"attribute:Country Name"}),
+			client.WithIncrementalSync(24*time.Hour),
+		)
+		require.Nil(t, err)
+	})
+}
@@ -0,0 +1,186 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// audienceBuilder struct is responsible for syncing audience resources and their members.
+// It is used by the connector to expose the Percipio audiences admins assign content to.
+// It holds a reference to the API client and the audience resource type descriptor.
+// This structure organizes the context needed for all audience-related synchronization operations.
+// Instances are created by the `newAudienceBuilder` function.
+type audienceBuilder struct {
+	client       *client.Client
+	resourceType *v2.ResourceType
+}
+
+// ResourceType method returns the resource type descriptor for audiences.
+// It implements the `ResourceType` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method returns the static `audienceResourceType` object defined for this connector.
+// Which informs the baton-sdk about the type of resource this syncer is responsible for.
+// This implementation returns a pre-defined object.
+func (o *audienceBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// audienceResource function creates a new `v2.Resource` from a Percipio audience object.
+// It implements the mapping from the provider's audience data model to the baton-sdk's resource model.
+// The function records the audience's ID, name and description in the group trait's profile.
+// Which lets audiences be reviewed like any other group.
+// This implementation falls back to the audience ID when the audience has no name.
+func audienceResource(audience client.Audience, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	displayName := audience.Name
+	if displayName == "" {
+		displayName = audience.Id
+	}
+
+	return resourceSdk.NewGroupResource(
+		displayName,
+		audienceResourceType,
+		audience.Id,
+		[]resourceSdk.GroupTraitOption{
+			resourceSdk.WithGroupProfile(map[string]interface{}{
+				"id":          audience.Id,
+				"name":        audience.Name,
+				"description": audience.Description,
+			}),
+		},
+		resourceSdk.WithDescription(audience.Description),
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+// List method fetches a page of audiences and returns them as `v2.Resource` objects.
+// It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method calls the Percipio API to get a page of audiences and transforms each one into a group resource.
+// Which enables the baton-sdk to paginate through all audiences in the upstream system.
+// This implementation uses the `client.ParseUserPaginationToken` and `client.GetUserNextToken` functions to handle pagination logic,
+// and only lists audiences under the organization.
+func (o *audienceBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	logger := ctxzap.Extract(ctx)
+	logger.Debug("Starting Audiences List", zap.String("token", pToken.Token))
+
+	var outputAnnotations annotations.Annotations
+	offset, limit, err := client.ParseUserPaginationToken(pToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	audiences, total, ratelimitData, err := o.client.GetAudiences(ctx, offset, limit)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	outputResources := make([]*v2.Resource, 0, len(audiences))
+	for _, audience := range audiences {
+		resource, err := audienceResource(audience, parentResourceID)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		outputResources = append(outputResources, resource)
+	}
+
+	return outputResources, client.GetUserNextToken(ctx, offset, limit, total), outputAnnotations, nil
+}
+
+// Entitlements method returns the entitlements for an audience resource.
+// It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method defines the 'member' entitlement for a given audience.
+// Which allows Baton to review who receives the content assigned to an audience.
+// This implementation returns a static list of one assignment entitlement.
+func (o *audienceBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			memberEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("Audience %s %s", resource.DisplayName, memberEntitlement)),
+			entitlement.WithDescription(fmt.Sprintf("Member of audience %s in Percipio", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// Grants method fetches a page of an audience's members and returns them as grants.
+// It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method calls the Percipio API for one page of the audience's users and grants each of them the 'member' entitlement.
+// Which turns audience membership into reviewable access.
+// This implementation paginates with the same offset tokens as the user list.
+func (o *audienceBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	pToken *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	var outputAnnotations annotations.Annotations
+	offset, limit, err := client.ParseUserPaginationToken(pToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	users, total, ratelimitData, err := o.client.GetAudienceUsers(ctx, resource.Id.Resource, offset, limit)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	grants := make([]*v2.Grant, 0, len(users))
+	for _, user := range users {
+		principalId, err := resourceSdk.NewResourceID(userResourceType, user.Id)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		grants = append(grants, grant.NewGrant(resource, memberEntitlement, principalId))
+	}
+
+	return grants, client.GetUserNextToken(ctx, offset, limit, total), outputAnnotations, nil
+}
+
+// newAudienceBuilder function creates a new `audienceBuilder`.
+// It implements the constructor for the audience resource syncer.
+// The function initializes an `audienceBuilder` with an API client and the audience resource type.
+// Which provides a configured syncer ready to be used by the main connector.
+// This implementation sets up the builder with its required dependencies.
+func newAudienceBuilder(client *client.Client) *audienceBuilder {
+	return &audienceBuilder{
+		client:       client,
+		resourceType: audienceResourceType,
+	}
+}
@@ -0,0 +1,83 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-percipio/test"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudiences(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should list audiences as groups", func(t *testing.T) {
+		server := test.FixturesServer()
+		defer server.Close()
+
+		percipioClient, err := client.New(ctx, server.URL, "mock", "token")
+		require.Nil(t, err)
+
+		a := newAudienceBuilder(percipioClient)
+		resources, _, listAnnotations, err := a.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, listAnnotations)
+		require.Len(t, resources, 1)
+		require.Equal(t, audienceResourceType.Id, resources[0].Id.ResourceType)
+		require.Equal(t, "00000000-0000-0000-0000-0000000000c1", resources[0].Id.Resource)
+		require.Equal(t, "Engineering", resources[0].DisplayName)
+	})
+
+	t.Run("should grant membership to audience users", func(t *testing.T) {
+		server := test.FixturesServer()
+		defer server.Close()
+
+		percipioClient, err := client.New(ctx, server.URL, "mock", "token")
+		require.Nil(t, err)
+
+		a := newAudienceBuilder(percipioClient)
+		audience, err := audienceResource(client.Audience{
+			Id:   "00000000-0000-0000-0000-0000000000c1",
+			Name: "Engineering",
+		}, organizationResourceID("mock"))
+		require.Nil(t, err)
+
+		grants, _, _, err := a.Grants(ctx, audience, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+		require.Equal(t, "00000000-0000-0000-0000-000000000001", grants[0].Principal.Id.Resource)
+		require.Equal(t, entitlement.NewEntitlementID(audience, memberEntitlement), grants[0].Entitlement.Id)
+	})
+
+	t.Run("should scope the report to configured audiences", func(t *testing.T) {
+		var requested client.ReportConfigurations
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			if request.Method == http.MethodPost && strings.Contains(request.URL.Path, "learning-activity") {
+				_ = json.NewDecoder(request.Body).Decode(&requested)
+			}
+			_, _ = writer.Write([]byte(`{"id":"report-id","status":"PENDING"}`))
+		}))
+		defer server.Close()
+
+		percipioClient, err := client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			client.WithReportAudiences([]string{"audience-1", "audience-2", "audience-1"}),
+		)
+		require.Nil(t, err)
+
+		_, err = percipioClient.GenerateLearningActivityReport(ctx)
+		require.Nil(t, err)
+		require.Equal(t, "audience-1,audience-2", requested.Audience)
+	})
+}
@@ -0,0 +1,124 @@
+package connector
+
+import (
+	"context"
+	"sync"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// catalogItem struct records one synced item of the Percipio catalog.
+// It is used by the `contentCatalog` to hand the same item to every content builder.
+// It holds the catalog item as returned by the API and the resource it maps to, without a parent.
+// This structure organizes the result of `courseResource`, so the content type rules are applied once per item.
+// Instances are created by the `load` method of `contentCatalog`.
+type catalogItem struct {
+	course   client.Course
+	resource *v2.Resource
+}
+
+// contentCatalog struct is an in-memory snapshot of the synced Percipio catalog.
+// It is used by the course, assessment, channel and journey builders, which all derive their resources from catalog items.
+// It holds the API client, the course scope settings and, once loaded, the synced items in catalog order.
+// This structure organizes a single catalog traversal shared by several builders instead of one traversal per builder.
+// Instances are created by the `newContentCatalog` function, once per call to `ResourceSyncers`, and emptied after every sync by `reset`.
+type contentCatalog struct {
+	client       *client.Client
+	limitCourses mapset.Set[string]
+	contentTypes mapset.Set[string]
+
+	mu     sync.Mutex
+	items  []catalogItem
+	loaded bool
+}
+
+// newContentCatalog function creates a new `contentCatalog`.
+// It implements the constructor for the shared catalog snapshot.
+// The function initializes the catalog with an API client, a set of courses to limit the sync, and the content types configured on the client.
+// Which lets the connector hand the same catalog to every builder that needs it.
+// This implementation does not call the API until the catalog is first needed.
+func newContentCatalog(client *client.Client, limitCourses mapset.Set[string]) *contentCatalog {
+	return &contentCatalog{
+		client:       client,
+		limitCourses: limitCourses,
+		contentTypes: mapset.NewSet(client.PercipioContentTypes()...),
+	}
+}
+
+// all method returns every synced item of the Percipio catalog.
+// It implements the lookup shared by the content builders.
+// The method walks the catalog with `load` on first use and caches the result until the next `reset`.
+// Which keeps the number of catalog traversals per sync at one regardless of how many builders read the catalog.
+// This implementation only caches a complete traversal, so a failed traversal is retried on the next call.
+func (c *contentCatalog) all(ctx context.Context) ([]catalogItem, annotations.Annotations, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loaded {
+		return c.items, annotations.Annotations{}, nil
+	}
+
+	items, outputAnnotations, err := c.load(ctx)
+	if err != nil {
+		return nil, outputAnnotations, err
+	}
+
+	ctxzap.Extract(ctx).Debug("loaded content catalog", zap.Int("items", len(items)))
+	c.items = items
+	c.loaded = true
+	return c.items, outputAnnotations, nil
+}
+
+// reset method drops the cached catalog items.
+// It implements the `syncCache` interface used by the connector's `Cleanup`.
+// The method clears the snapshot so the next call to `all` walks the catalog again.
+// Which keeps a long-running connector from serving the first sync's catalog to every later sync.
+// This implementation keeps the client and the course scope settings.
+func (c *contentCatalog) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = nil
+	c.loaded = false
+}
+
+// load method walks the whole catalog and keeps the items that are synced.
+// It implements the catalog traversal behind the `all` cache.
+// The method pages through the catalog with `fetchContentPage` and maps each item with `courseResource`.
+// Which is the only way to enumerate content, channels and journeys, since the latter are only exposed as catalog associations.
+// This implementation drops inactive items, items outside the configured content types, and items returned more than once.
+func (c *contentCatalog) load(ctx context.Context) ([]catalogItem, annotations.Annotations, error) {
+	var outputAnnotations annotations.Annotations
+	seen := mapset.NewThreadUnsafeSet[string]()
+	items := make([]catalogItem, 0)
+	pToken := &pagination.Token{}
+	for {
+		courses, nextToken, pageAnnotations, err := fetchContentPage(ctx, c.client, c.limitCourses, pToken)
+		outputAnnotations = pageAnnotations
+		if err != nil {
+			return nil, outputAnnotations, err
+		}
+
+		for _, course := range courses {
+			resource, err := courseResource(ctx, course, nil, c.contentTypes)
+			if err != nil {
+				return nil, outputAnnotations, err
+			}
+			if resource == nil || !seen.Add(course.Id) {
+				continue
+			}
+			items = append(items, catalogItem{course: course, resource: resource})
+		}
+
+		if nextToken == "" {
+			return items, outputAnnotations, nil
+		}
+		pToken = &pagination.Token{Token: nextToken}
+	}
+}
@@ -0,0 +1,174 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+)
+
+const completedAllEntitlement = "completed_all"
+
+// channelBuilder struct is responsible for syncing channel resources and their roll-up completion grants.
+// It is used by the connector to expose Percipio channels, the curricula that group courses.
+// It holds a reference to the API client, the channel resource type descriptor, and an index of channels built from the catalog.
+// This structure organizes the context needed for all channel-related synchronization operations.
+// Instances are created by the `newChannelBuilder` function.
+type channelBuilder struct {
+	client       *client.Client
+	resourceType *v2.ResourceType
+	index        *contentGroupIndex
+}
+
+// ResourceType method returns the resource type descriptor for channels.
+// It implements the `ResourceType` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method returns the static `channelResourceType` object defined for this connector.
+// Which informs the baton-sdk about the type of resource this syncer is responsible for.
+// This implementation returns a pre-defined object.
+func (o *channelBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// channelTitle function picks a display name for a channel referenced by a course.
+// It implements the naming of channel resources, which have no catalog entry of their own.
+// The function prefers the en-US localized channel title and falls back to the association's title and then the channel ID.
+// Which keeps channel names consistent with how course names are chosen.
+// This implementation only considers localized channels with the same ID.
+func channelTitle(course client.Course, channel client.Channel) string {
+	for _, localized := range course.Associations.LocalizedChannels {
+		if localized.Id == channel.Id && localized.LocaleCode == "en-US" && localized.Title != "" {
+			return localized.Title
+		}
+	}
+	if channel.Title != "" {
+		return channel.Title
+	}
+	return channel.Id
+}
+
+// channelsOf function returns the channels a synced catalog item contributes to.
+// It implements the `groupsOf` callback of the channel index.
+// The function maps each of the course's channel associations to a `contentGroupRef`.
+// Which records channel membership through the `completed_all` entitlement, since courses stay under the organization.
+// This implementation ignores content synced as anything other than a course.
+func channelsOf(course client.Course, resource *v2.Resource) []contentGroupRef {
+	if resource.Id.ResourceType != courseResourceType.Id {
+		return nil
+	}
+
+	refs := make([]contentGroupRef, 0, len(course.Associations.Channels))
+	for _, channel := range course.Associations.Channels {
+		refs = append(refs, contentGroupRef{
+			id:    channel.Id,
+			title: channelTitle(course, channel),
+		})
+	}
+	return refs
+}
+
+// List method returns a page of channels as `v2.Resource` objects.
+// It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method builds the channel index on first use and returns the requested slice of channels, sorted by ID.
+// Which enables the baton-sdk to sync every channel that contains at least one synced course.
+// This implementation delegates to the shared `contentGroupIndex`.
+func (o *channelBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return o.index.list(ctx, o.resourceType, parentResourceID, pToken)
+}
+
+// Entitlements method returns the entitlements for a channel resource.
+// It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method defines the 'completed_all' roll-up entitlement for a given channel.
+// Which allows Baton to certify users against a whole curriculum rather than individual courses.
+// This implementation returns a static list of one assignment entitlement.
+func (o *channelBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			completedAllEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("Channel %s %s", resource.DisplayName, completedAllEntitlement)),
+			entitlement.WithDescription(fmt.Sprintf("Completed all courses in channel %s in Percipio", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// Grants method computes the roll-up completion grants for a channel resource.
+// It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method loads the learning activity report and grants 'completed_all' to the users who completed every synced course in the channel.
+// Which derives curriculum completion from the same report data as the course grants.
+// This implementation returns no grants for a channel without synced courses.
+func (o *channelBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	members, outputAnnotations, err := o.index.members(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	err = loadLearningActivityReport(ctx, o.client, &outputAnnotations)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	completedAll, err := usersWithStatusOnAll(ctx, o.client.StatusesStore, members, func(status string) bool {
+		return status == client.StatusCompleted
+	})
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	grants := make([]*v2.Grant, 0, completedAll.Cardinality())
+	for _, userId := range completedAll.ToSlice() {
+		principalId, err := resourceSdk.NewResourceID(userResourceType, userId)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		grants = append(grants, grant.NewGrant(resource, completedAllEntitlement, principalId))
+	}
+
+	return grants, "", outputAnnotations, nil
+}
+
+// newChannelBuilder function creates a new `channelBuilder`.
+// It implements the constructor for the channel resource syncer.
+// The function initializes a `channelBuilder` with an API client, the channel resource type, and a channel index over the shared content catalog.
+// Which provides a configured syncer ready to be used by the main connector.
+// This implementation leaves the channel index empty until it is first needed.
+func newChannelBuilder(client *client.Client, catalog *contentCatalog) *channelBuilder {
+	return &channelBuilder{
+		client:       client,
+		resourceType: channelResourceType,
+		index:        newContentGroupIndex(catalog, channelsOf),
+	}
+}
@@ -0,0 +1,71 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-percipio/test"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannels(t *testing.T) {
+	ctx := context.Background()
+	server := test.FixturesServer()
+	defer server.Close()
+
+	percipioClient, err := client.New(
+		ctx,
+		server.URL,
+		"mock",
+		"token",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should list channels and keep courses under the organization", func(t *testing.T) {
+		c := newChannelBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		resources, nextToken, listAnnotations, err := c.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, listAnnotations)
+		require.Empty(t, nextToken)
+		require.Len(t, resources, 1)
+		require.Equal(t, channelResourceType.Id, resources[0].Id.ResourceType)
+		require.Equal(t, "00000000-0000-0000-0000-000000000000", resources[0].Id.Resource)
+		require.Equal(t, "Microsoft 365 Administration", resources[0].DisplayName)
+
+		courses, _, _, err := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil)).List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		require.NotEmpty(t, courses)
+		for _, course := range courses {
+			require.Equal(t, organizationResourceID("mock"), course.ParentResourceId)
+		}
+	})
+
+	t.Run("should grant completed_all only once every course is completed", func(t *testing.T) {
+		c := newChannelBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		resources, _, _, err := c.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, resources, 1)
+
+		grants, _, _, err := c.Grants(ctx, resources[0], &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 0)
+
+		err = percipioClient.StatusesStore.Add(ctx, &client.ReportEntry{
+			ContentUUID: "00000000-0000-0000-0000-000000000000",
+			UserUUID:    "00000000-0000-0000-0000-000000000001",
+			Status:      "Completed",
+		})
+		require.Nil(t, err)
+
+		grants, _, _, err = c.Grants(ctx, resources[0], &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+		require.Equal(t, "00000000-0000-0000-0000-000000000001", grants[0].Principal.Id.Resource)
+		require.Equal(t, entitlement.NewEntitlementID(resources[0], completedAllEntitlement), grants[0].Entitlement.Id)
+	})
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+)
+
+const assignmentDueDateLayout = "2006-01-02"
+
+// WithAssignmentDueDays function configures the due date of content assignments created by the connector.
+// It implements the option used by the connector to apply the `assignment-due-days` configuration field.
+// The function stores how many days after provisioning an assignment is due.
+// Which lets mandatory training pushed from an access request carry a deadline.
+// This implementation leaves assignments without a due date when `days` is not positive.
+func WithAssignmentDueDays(days int) Option {
+	return func(c *Client) {
+		if days <= 0 {
+			c.assignmentDueIn = 0
+			return
+		}
+		c.assignmentDueIn = time.Duration(days) * 24 * time.Hour
+	}
+}
+
+// assignmentPath function builds the API path of a single assignment.
+// It implements the path construction shared by the assignment operations that target one assignment.
+// The function escapes the assignment ID before it is combined with the organization ID by `getUrl`.
+// Which keeps IDs containing reserved characters from changing the request path.
+// This implementation mirrors the escaping used by `GetAudienceUsers`.
+func assignmentPath(assignmentId string) string {
+	return fmt.Sprintf(ApiPathAssignment, "%s", strings.ReplaceAll(url.PathEscape(assignmentId), "%", "%%"))
+}
+
+// GetAssignments method fetches a single page of content assignments from the Percipio API.
+// It implements the assignment retrieval operation required by the course grant builder and provisioning.
+// The method filters by content ID and, when given, by user ID, and calls the internal `get` helper against the `ApiPathAssignments` endpoint.
+// Which enables the connector to sync the 'assigned' entitlement and to find the assignments to remove on revoke.
+// This implementation shares the offset pagination and `x-total-count` handling of `GetUsers`.
+func (c *Client) GetAssignments(
+	ctx context.Context,
+	contentId string,
+	userId string,
+	offset int,
+	limit int,
+) (
+	[]Assignment,
+	int,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := map[string]interface{}{
+		"contentId": contentId,
+		"max":       limit,
+		"offset":    offset,
+	}
+	if userId != "" {
+		query["userId"] = userId
+	}
+
+	var target []Assignment
+	response, ratelimitData, err := c.get(ctx, ApiPathAssignments, query, &target)
+	if err != nil {
+		return nil, 0, ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	total, err := getTotalCount(response)
+	if err != nil {
+		return nil, 0, ratelimitData, err
+	}
+	return target, total, ratelimitData, nil
+}
+
+// CreateAssignment method assigns a content item to a user in Percipio.
+// It implements the provisioning operation behind the course 'assigned' entitlement.
+// The method sends a POST request to the `ApiPathAssignments` endpoint with the content ID, the user ID and, if configured, a due date.
+// Which pushes mandatory training into the learner's Percipio assignments.
+// This implementation computes the due date from `WithAssignmentDueDays` relative to the current day.
+func (c *Client) CreateAssignment(
+	ctx context.Context,
+	contentId string,
+	userId string,
+) (
+	*Assignment,
+	*v2.RateLimitDescription,
+	error,
+) {
+	body := Assignment{
+		ContentId: contentId,
+		UserId:    userId,
+	}
+	if c.assignmentDueIn > 0 {
+		body.DueDate = time.Now().UTC().Add(c.assignmentDueIn).Format(assignmentDueDateLayout)
+	}
+
+	var target Assignment
+	response, ratelimitData, err := c.post(ctx, ApiPathAssignments, body, &target)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	return &target, ratelimitData, nil
+}
+
+// DeleteAssignment method removes a content assignment from Percipio.
+// It implements the deprovisioning operation behind the course 'assigned' entitlement.
+// The method sends a DELETE request for the assignment to the `ApiPathAssignment` endpoint.
+// Which takes the content off the learner's list of mandatory training.
+// This implementation returns the typed API error unchanged so callers can treat an already removed assignment as revoked.
+func (c *Client) DeleteAssignment(
+	ctx context.Context,
+	assignmentId string,
+) (
+	*v2.RateLimitDescription,
+	error,
+) {
+	response, ratelimitData, err := c.delete(ctx, assignmentPath(assignmentId))
+	if err != nil {
+		return ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	return ratelimitData, nil
+}
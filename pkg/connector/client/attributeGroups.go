@@ -0,0 +1,162 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+)
+
+// AttributeGroupMapping struct describes one user value the connector turns into groups.
+// It is used by the attribute group builder to decide which values to read from users and report rows.
+// It holds the `Source` of the value, either a custom attribute or a learning activity report field, and its `Name`.
+// This structure organizes the `attribute-groups` configuration so each distinct value becomes one group.
+// Instances are created by the `WithAttributeGroups` option.
+type AttributeGroupMapping struct {
+	Source string
+	Name   string
+}
+
+// reportUserFields struct collects the organization fields of each user seen in the learning activity report.
+// It is used by `GetLearningActivityReport` when a configured attribute group reads a report field.
+// It holds, per user ID, the value of every requested report field.
+// This structure organizes data the report carries but the user management API does not, such as department and division.
+// Instances are created by the `WithAttributeGroups` option and filled while the report is streamed.
+type reportUserFields struct {
+	mu     sync.Mutex
+	fields []string
+	values map[string]map[string]string
+}
+
+// WithAttributeGroups function configures the user values synthesized into groups.
+// It implements the option used by the connector to apply the `attribute-groups` configuration field.
+// The function parses each `source:name` entry and, when any entry reads a report field, enables collecting report fields per user.
+// Which lets reviewers slice course completion by department or cost center without exporting data.
+// This implementation skips entries with an unknown source or report field; the configuration schema rejects them earlier,
+// and `New` rejects report fields combined with `WithIncrementalSync`.
+func WithAttributeGroups(mappings []string) Option {
+	return func(c *Client) {
+		c.attributeGroups = nil
+		c.reportUserFields = nil
+		var fields []string
+		for _, mapping := range mappings {
+			source, name, ok := strings.Cut(mapping, ":")
+			if !ok || name == "" {
+				continue
+			}
+			switch source {
+			case config.AttributeGroupSourceAttribute:
+			case config.AttributeGroupSourceReport:
+				if !slices.Contains(config.ReportGroupFields, name) {
+					continue
+				}
+				fields = append(fields, name)
+			default:
+				continue
+			}
+			c.attributeGroups = append(c.attributeGroups, AttributeGroupMapping{
+				Source: source,
+				Name:   name,
+			})
+		}
+		if len(fields) > 0 {
+			c.reportUserFields = &reportUserFields{
+				fields: fields,
+				values: make(map[string]map[string]string),
+			}
+		}
+	}
+}
+
+// AttributeGroups method returns the configured attribute group mappings.
+// It implements the lookup used by the connector to decide whether to sync attribute groups at all.
+// The method returns the mappings parsed by `WithAttributeGroups`.
+// Which keeps the configuration in one place, like the content type scope.
+// This implementation returns a copy so callers cannot change the client's configuration.
+func (c *Client) AttributeGroups() []AttributeGroupMapping {
+	return slices.Clone(c.attributeGroups)
+}
+
+// ReportUserFields method returns the report field values collected for every user.
+// It implements the lookup used by the attribute group builder for report-sourced groups.
+// The method returns a snapshot of the user ID to field name to value map filled while loading the report.
+// Which provides organization data for every user with activity in the loaded report.
+// This implementation returns nil when no report field is configured.
+func (c *Client) ReportUserFields() map[string]map[string]string {
+	if c.reportUserFields == nil {
+		return nil
+	}
+
+	c.reportUserFields.mu.Lock()
+	defer c.reportUserFields.mu.Unlock()
+	snapshot := make(map[string]map[string]string, len(c.reportUserFields.values))
+	for userId, values := range c.reportUserFields.values {
+		snapshot[userId] = values
+	}
+	return snapshot
+}
+
+// validateAttributeGroups method checks that the configured attribute groups can be built from the loaded report.
+// It implements the configuration check run by `New` once every option has been applied.
+// The method refuses report-sourced groups when incremental sync is enabled.
+// Which avoids silently dropping users from report groups, since an incremental report only carries users with activity in its window.
+// This implementation returns nil when no report field is configured or incremental sync is off.
+func (c *Client) validateAttributeGroups() error {
+	if c.reportUserFields == nil || !c.incrementalSync {
+		return nil
+	}
+	return fmt.Errorf(
+		"report attribute groups %v cannot be used with incremental sync, which only reports users with recent activity",
+		c.reportUserFields.fields,
+	)
+}
+
+// record method stores the requested organization fields of a report row.
+// It implements the collection step run for every row streamed from the learning activity report.
+// The method reads each configured field from the row and keeps the last non-empty value per user.
+// Which captures the user's organization data regardless of which content the row is about.
+// This implementation is a no-op on a nil receiver, so callers need not check whether collection is enabled.
+func (f *reportUserFields) record(row *ReportEntry) {
+	if f == nil || row.UserUUID == "" {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, field := range f.fields {
+		value := reportFieldValue(row, field)
+		if value == "" {
+			continue
+		}
+		values, ok := f.values[row.UserUUID]
+		if !ok {
+			values = make(map[string]string, len(f.fields))
+			f.values[row.UserUUID] = values
+		}
+		values[field] = value
+	}
+}
+
+// reportFieldValue function reads a named organization field from a report row.
+// It implements the mapping between `config.ReportGroupFields` names and `ReportEntry` fields.
+// The function switches on the report's JSON field name.
+// Which keeps the configuration vocabulary identical to the report's own column names.
+// This implementation returns an empty string for unknown field names.
+func reportFieldValue(row *ReportEntry, field string) string {
+	switch field {
+	case config.ReportGroupFieldBusinessUnit:
+		return row.BusinessUnit
+	case config.ReportGroupFieldCostCenterCode:
+		return row.CostCenterCode
+	case config.ReportGroupFieldDeptName:
+		return row.DeptName
+	case config.ReportGroupFieldDivision:
+		return row.Division
+	case config.ReportGroupFieldGeo:
+		return row.Geo
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	TokenUrlDefault   = "https://oauth2-provider.skillsoft.com/oauth2/token"
+	TokenScopeDefault = "api"
+	tokenRefreshSkew  = 2 * time.Minute
+)
+
+// tokenSource interface abstracts how the client obtains a bearer token for a request.
+// It is used by `doRequest` and `pollLearningActivityReport` to build the `Authorization` header.
+// It holds two operations: `Token` returns a currently valid access token, and `Invalidate` discards it.
+// This structure organizes static API tokens and refreshable service-account credentials behind one contract.
+// Instances are created by the `New` client function or replaced by the `WithClientCredentials` option.
+type tokenSource interface {
+	Token(ctx context.Context) (string, error)
+	Invalidate() bool
+}
+
+// staticTokenSource type is a tokenSource backed by a fixed Percipio bearer token.
+// It is used when the connector is configured with the `api-token` field.
+// It holds the raw token string exactly as provided by the operator.
+// This structure organizes the legacy authentication mode so it shares the same code path as OAuth2.
+// Instances are created by the `New` client function.
+type staticTokenSource string
+
+// Token method returns the configured bearer token.
+// It implements the `Token` method required by the `tokenSource` interface.
+// The method returns the token unchanged and never fails.
+// Which keeps static tokens working without any additional configuration.
+// This implementation ignores the context because no network call is made.
+func (s staticTokenSource) Token(_ context.Context) (string, error) {
+	return string(s), nil
+}
+
+// Invalidate method reports that a static token cannot be refreshed.
+// It implements the `Invalidate` method required by the `tokenSource` interface.
+// The method returns false so callers do not retry a request that failed with 401.
+// Which avoids sending a second request that is guaranteed to fail the same way.
+// This implementation has no state to clear.
+func (s staticTokenSource) Invalidate() bool {
+	return false
+}
+
+// clientCredentialsTokenSource struct mints and caches access tokens for a Percipio service account.
+// It is used when the connector is configured with a client ID and client secret instead of an API token.
+// It holds the OAuth2 client credentials configuration, the HTTP client used for the token endpoint, and the cached token.
+// This structure organizes token reuse so that a new token is only requested shortly before the current one expires.
+// Instances are created by the `WithClientCredentials` option.
+type clientCredentialsTokenSource struct {
+	config     *clientcredentials.Config
+	httpClient *http.Client
+	mu         sync.Mutex
+	token      *oauth2.Token
+}
+
+// Token method returns a cached access token, minting a new one when needed.
+// It implements the `Token` method required by the `tokenSource` interface.
+// The method reuses the cached token until it is within `tokenRefreshSkew` of expiry, then requests a fresh one from the token endpoint.
+// Which keeps long-running syncs and report polls authenticated past the lifetime of a single token.
+// This implementation serializes refreshes with a mutex so concurrent callers share a single token request,
+// and reports a rejected token request as an `APIError`.
+func (s *clientCredentialsTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && s.token.AccessToken != "" &&
+		(s.token.Expiry.IsZero() || time.Until(s.token.Expiry) > tokenRefreshSkew) {
+		return s.token.AccessToken, nil
+	}
+
+	if s.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, s.httpClient)
+	}
+
+	token, err := s.config.Token(ctx)
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.Response != nil {
+			return "", &APIError{
+				Kind:       classifyStatusCode(retrieveErr.Response.StatusCode),
+				StatusCode: retrieveErr.Response.StatusCode,
+				Code:       retrieveErr.ErrorCode,
+				Message:    retrieveErr.ErrorDescription,
+				Method:     http.MethodPost,
+				Url:        s.config.TokenURL,
+				cause:      err,
+			}
+		}
+		return "", fmt.Errorf("failed to obtain percipio access token: %w", err)
+	}
+
+	s.token = token
+	return token.AccessToken, nil
+}
+
+// Invalidate method discards the cached access token.
+// It implements the `Invalidate` method required by the `tokenSource` interface.
+// The method clears the cached token so the next call to `Token` mints a new one.
+// Which lets a request that failed with 401 be retried once with fresh credentials.
+// This implementation always reports that a retry is worthwhile.
+func (s *clientCredentialsTokenSource) Invalidate() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = nil
+	return true
+}
+
+// Option type configures optional behavior of the Percipio API Client.
+// It is used by the `New` client function to apply settings beyond the required base URL, organization and token.
+// It holds a function that mutates the client after its defaults have been set.
+// This structure organizes optional client settings so new ones can be added without changing the constructor signature.
+// Instances are created by the `With...` option functions in this package.
+type Option func(*Client)
+
+// WithClientCredentials function configures the client to authenticate as a Percipio service account.
+// It implements the OAuth2 client credentials alternative to a static bearer token.
+// The function replaces the client's token source with one that mints tokens from `tokenUrl` using the given client ID and secret.
+// Which allows syncs to outlive the expiry of any individual access token.
+// This implementation falls back to `TokenUrlDefault` when no token URL is provided.
+func WithClientCredentials(clientId string, clientSecret string, tokenUrl string) Option {
+	if tokenUrl == "" {
+		tokenUrl = TokenUrlDefault
+	}
+
+	return func(c *Client) {
+		c.tokenSource = &clientCredentialsTokenSource{
+			config: &clientcredentials.Config{
+				ClientID:     clientId,
+				ClientSecret: clientSecret,
+				TokenURL:     tokenUrl,
+				Scopes:       []string{TokenScopeDefault},
+			},
+			httpClient: c.httpClient,
+		}
+	}
+}
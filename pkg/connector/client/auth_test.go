@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	newTokenServer := func(t *testing.T, expiresIn int, mints *atomic.Int32) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			mint := mints.Add(1)
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(writer, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, mint, expiresIn)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	// newRejectingServer answers the first request with 401 and every later one with body.
+	newRejectingServer := func(t *testing.T, body string, authorizations *[]string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			*authorizations = append(*authorizations, request.Header.Get("Authorization"))
+			if len(*authorizations) == 1 {
+				writer.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = writer.Write([]byte(body))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	newCredentialsClient := func(t *testing.T, apiUrl string, tokenUrl string) *Client {
+		percipioClient, err := New(
+			ctx,
+			apiUrl,
+			"mock",
+			"",
+			WithClientCredentials("client-id", "client-secret", tokenUrl),
+			WithReportPolling(10*time.Millisecond, 10*time.Millisecond, time.Second),
+		)
+		require.Nil(t, err)
+		return percipioClient
+	}
+
+	t.Run("should mint a new token and retry once after a 401", func(t *testing.T) {
+		t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+		var mints atomic.Int32
+		tokenServer := newTokenServer(t, 3600, &mints)
+		authorizations := make([]string, 0)
+		apiServer := newRejectingServer(t, `{"id":"00000000-0000-0000-0000-000000000001"}`, &authorizations)
+		percipioClient := newCredentialsClient(t, apiServer.URL, tokenServer.URL)
+
+		user, _, err := percipioClient.GetUser(ctx, "00000000-0000-0000-0000-000000000001")
+		require.Nil(t, err)
+		require.Equal(t, "00000000-0000-0000-0000-000000000001", user.Id)
+		require.Equal(t, int32(2), mints.Load())
+		require.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, authorizations)
+	})
+
+	t.Run("should mint a new token and retry once when a report poll gets a 401", func(t *testing.T) {
+		var mints atomic.Int32
+		tokenServer := newTokenServer(t, 3600, &mints)
+		authorizations := make([]string, 0)
+		apiServer := newRejectingServer(t, `[{"userUuid":"user-1"}]`, &authorizations)
+		percipioClient := newCredentialsClient(t, apiServer.URL, tokenServer.URL)
+
+		rows := 0
+		_, err := percipioClient.pollLearningActivityReport(ctx, "report-1", func(body io.Reader) error {
+			count, err := DecodeReport(body, func(row *ReportEntry) error {
+				return nil
+			})
+			rows = count
+			return err
+		})
+		require.Nil(t, err)
+		require.Equal(t, 1, rows)
+		require.Equal(t, int32(2), mints.Load())
+		require.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, authorizations)
+	})
+
+	t.Run("should not retry a second 401", func(t *testing.T) {
+		t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+		var mints atomic.Int32
+		tokenServer := newTokenServer(t, 3600, &mints)
+		var requests atomic.Int32
+		apiServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requests.Add(1)
+			writer.WriteHeader(http.StatusUnauthorized)
+		}))
+		t.Cleanup(apiServer.Close)
+		percipioClient := newCredentialsClient(t, apiServer.URL, tokenServer.URL)
+
+		_, _, err := percipioClient.GetUser(ctx, "00000000-0000-0000-0000-000000000001")
+		require.NotNil(t, err)
+		require.Equal(t, int32(2), mints.Load())
+		require.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("should reuse a token until it is within the refresh skew", func(t *testing.T) {
+		var mints atomic.Int32
+		tokenServer := newTokenServer(t, int((tokenRefreshSkew + time.Hour).Seconds()), &mints)
+		percipioClient := newCredentialsClient(t, "http://localhost", tokenServer.URL)
+
+		for range 3 {
+			token, err := percipioClient.tokenSource.Token(ctx)
+			require.Nil(t, err)
+			require.Equal(t, "token-1", token)
+		}
+		require.Equal(t, int32(1), mints.Load())
+	})
+
+	t.Run("should refresh a token that expires within the refresh skew", func(t *testing.T) {
+		var mints atomic.Int32
+		tokenServer := newTokenServer(t, int((tokenRefreshSkew / 2).Seconds()), &mints)
+		percipioClient := newCredentialsClient(t, "http://localhost", tokenServer.URL)
+
+		for i := range 3 {
+			token, err := percipioClient.tokenSource.Token(ctx)
+			require.Nil(t, err)
+			require.Equal(t, fmt.Sprintf("token-%d", i+1), token)
+		}
+		require.Equal(t, int32(3), mints.Load())
+	})
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// uncachedKey type is the context key marking requests that must not be served from the HTTP cache.
+// It is used by `Uncached` and `isUncached` to carry the marker through a context.
+// It holds no data; only its presence in a context matters.
+// This structure organizes the marker as an unexported type so no other package can set or collide with it.
+// Instances are created by `Uncached`.
+type uncachedKey struct{}
+
+// Uncached function marks a context so that GET requests made with it bypass the HTTP cache.
+// It implements the opt-out used by provisioning, which must decide from the current state of Percipio.
+// The function wraps `ctx` with a marker read by `sendRequest`.
+// Which keeps a page cached during the sync, or before a previous grant, from hiding a change made since.
+// This implementation is needed because the uhttp cache ignores `Cache-Control` request headers.
+func Uncached(ctx context.Context) context.Context {
+	return context.WithValue(ctx, uncachedKey{}, true)
+}
+
+// isUncached function reports whether a context was marked by `Uncached`.
+// It implements the check made by `sendRequest` before choosing how to send a GET request.
+// The function looks the marker up in the context.
+// Which lets callers opt out of the cache without a separate set of client methods.
+// This implementation returns false for contexts without the marker.
+func isUncached(ctx context.Context) bool {
+	uncached, _ := ctx.Value(uncachedKey{}).(bool)
+	return uncached
+}
+
+// doUncached method sends a request with the underlying HTTP client, bypassing the uhttp response cache.
+// It implements the uncached counterpart of `uhttp.BaseHttpClient.Do` for requests made with an `Uncached` context.
+// The method reads the whole body, applies the `uhttp.DoOption` values to it, and leaves the body readable for error handling.
+// Which gives `sendRequest` the same response, rate limit data and decoding whichever path it takes.
+// This implementation neither reads nor populates the cache, and reports any non-2xx status as an error.
+func (c *Client) doUncached(request *http.Request, options ...uhttp.DoOption) (*http.Response, error) {
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return response, err
+	}
+
+	wrapperResponse := uhttp.WrapperResponse{
+		Header:     response.Header,
+		Status:     response.Status,
+		StatusCode: response.StatusCode,
+		Body:       body,
+	}
+	var optionErrors []error
+	for _, option := range options {
+		optionErr := option(&wrapperResponse)
+		if optionErr != nil {
+			optionErrors = append(optionErrors, optionErr)
+		}
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		optionErrors = append(optionErrors, fmt.Errorf("unexpected status code: %d", response.StatusCode))
+	}
+	return response, errors.Join(optionErrors...)
+}
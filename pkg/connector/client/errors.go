@@ -0,0 +1,268 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const apiErrorBodyLimit = 64 * 1024
+
+// ErrorKind type classifies failures returned by the Percipio API.
+// It is used by `APIError` to tell credential, permission, availability and report problems apart.
+// It holds one of the `ErrorKind...` constants declared in this package.
+// This structure organizes error classification so callers and alerting do not have to inspect HTTP status codes.
+// Instances are assigned by `newAPIError` and `newReportFailedError`.
+type ErrorKind string
+
+const (
+	ErrorKindAuthentication ErrorKind = "authentication"
+	ErrorKindPermission     ErrorKind = "permission"
+	ErrorKindNotFound       ErrorKind = "not_found"
+	ErrorKindRateLimit      ErrorKind = "rate_limit"
+	ErrorKindServer         ErrorKind = "server"
+	ErrorKindRequest        ErrorKind = "request"
+	ErrorKindReportFailed   ErrorKind = "report_failed"
+)
+
+// requestIdHeaders lists the response headers that may carry a Percipio request ID.
+// It is used by `newAPIError` when the error body does not include a request ID.
+// It holds header names in the order they are checked.
+// This variable keeps request ID discovery in one place so support tickets can reference the failing call.
+// The instance is a fixed lookup list and is never mutated.
+var requestIdHeaders = []string{"x-request-id", "x-correlation-id", "x-amzn-requestid"}
+
+// APIError struct describes a failed call to the Percipio API.
+// It is used by every request path of the client in place of untyped error strings.
+// It holds the error `Kind`, the HTTP status, the Percipio error code, message and request ID, the failing request, and any rate limit data.
+// This structure organizes failure details so the baton-sdk and operators can distinguish, for example, a revoked token from an outage.
+// Instances are created by `newAPIError` and `newReportFailedError`, and implement `GRPCStatus` for the baton-sdk.
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+	Code       string
+	Message    string
+	RequestId  string
+	Method     string
+	Url        string
+	RateLimit  *v2.RateLimitDescription
+	cause      error
+}
+
+// apiErrorBody struct is the union of the error document shapes returned by Percipio endpoints.
+// It is used by `parseAPIErrorBody` to decode an error response body.
+// It holds top-level `code`/`message` fields, OAuth2-style `error` fields, and a nested `errors` list.
+// This structure organizes lenient decoding because different Percipio services format errors differently.
+// Instances are created by unmarshaling an error response body.
+type apiErrorBody struct {
+	Code             any    `json:"code"`
+	ErrorCode        string `json:"errorCode"`
+	Message          string `json:"message"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	RequestId        string `json:"requestId"`
+	Errors           []struct {
+		Code    any    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Error method formats the API error for logs and error chains.
+// It implements the `error` interface.
+// The method includes the request, HTTP status, Percipio error code, request ID and message when they are known.
+// Which gives operators enough context to act on a failure without enabling debug logging.
+// This implementation omits empty fields.
+func (e *APIError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("percipio api error")
+	if e.Method != "" || e.Url != "" {
+		fmt.Fprintf(&builder, ": %s %s", e.Method, e.Url)
+	}
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&builder, " returned %d", e.StatusCode)
+	}
+	fmt.Fprintf(&builder, " (%s", e.Kind)
+	if e.Code != "" {
+		fmt.Fprintf(&builder, ", code %s", e.Code)
+	}
+	if e.RequestId != "" {
+		fmt.Fprintf(&builder, ", request id %s", e.RequestId)
+	}
+	builder.WriteString(")")
+	if e.Message != "" {
+		fmt.Fprintf(&builder, ": %s", e.Message)
+	}
+	return builder.String()
+}
+
+// Unwrap method returns the underlying transport error, if any.
+// It implements error unwrapping for `errors.Is` and `errors.As`.
+// The method returns the error reported by uhttp or net/http for the failing request.
+// Which keeps the original cause inspectable.
+// This implementation returns nil for errors built from a response body alone.
+func (e *APIError) Unwrap() error {
+	return e.cause
+}
+
+// GRPCCode method maps the API error to a gRPC status code.
+// It implements the classification consumed by the baton-sdk retry logic.
+// The method maps rate limit and server errors to `Unavailable`, so they are retried, and every other kind to a non-retryable code.
+// Which keeps the sdk from hammering Percipio with a revoked token while still riding out an outage.
+// This implementation falls back to `Unknown` for unclassified errors.
+func (e *APIError) GRPCCode() codes.Code {
+	switch e.Kind {
+	case ErrorKindAuthentication:
+		return codes.Unauthenticated
+	case ErrorKindPermission:
+		return codes.PermissionDenied
+	case ErrorKindNotFound:
+		return codes.NotFound
+	case ErrorKindRateLimit, ErrorKindServer:
+		return codes.Unavailable
+	case ErrorKindRequest:
+		if e.StatusCode == http.StatusConflict {
+			return codes.AlreadyExists
+		}
+		return codes.InvalidArgument
+	case ErrorKindReportFailed:
+		return codes.Aborted
+	default:
+		return codes.Unknown
+	}
+}
+
+// GRPCStatus method converts the API error into a gRPC status.
+// It implements the interface used by `status.FromError` and `status.Code` to recognize typed errors.
+// The method builds a status from `GRPCCode` and the error message, attaching rate limit data as a detail when present.
+// Which lets the baton-sdk wait until the rate limit resets before retrying.
+// This implementation returns the status without details if they cannot be attached.
+func (e *APIError) GRPCStatus() *status.Status {
+	st := status.New(e.GRPCCode(), e.Error())
+	if e.RateLimit == nil {
+		return st
+	}
+	withDetails, err := st.WithDetails(e.RateLimit)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// Retryable method reports whether the failed request may succeed if repeated.
+// It implements the check used by the report polling loop to keep waiting through transient failures.
+// The method returns true for rate limit and server errors.
+// Which matches the codes the baton-sdk itself retries.
+// This implementation is derived from `GRPCCode`.
+func (e *APIError) Retryable() bool {
+	return e.GRPCCode() == codes.Unavailable
+}
+
+// classifyStatusCode function maps an HTTP status code to an `ErrorKind`.
+// It implements the classification step shared by every request path.
+// The function groups 401, 403, 404/410, 429 and 5xx responses into their own kinds and treats any other 4xx as a bad request.
+// Which keeps the mapping from HTTP to error kinds consistent between uhttp and raw net/http calls.
+// This implementation classifies 408 as a server error, since it is retryable.
+func classifyStatusCode(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusUnauthorized:
+		return ErrorKindAuthentication
+	case statusCode == http.StatusForbidden:
+		return ErrorKindPermission
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		return ErrorKindNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimit
+	case statusCode == http.StatusRequestTimeout || statusCode >= 500:
+		return ErrorKindServer
+	default:
+		return ErrorKindRequest
+	}
+}
+
+// newAPIError function builds an `APIError` from a failed HTTP response.
+// It implements the translation of non-2xx responses from both uhttp and raw net/http requests.
+// The function classifies the status code, parses the error body for a Percipio code, message and request ID, and records the request.
+// Which replaces generic "error making request" strings with structured, classifiable errors.
+// This implementation reads at most `apiErrorBodyLimit` bytes of the body and falls back to response headers for the request ID.
+func newAPIError(resp *http.Response, method string, url string, cause error) *APIError {
+	apiErr := &APIError{
+		Kind:       classifyStatusCode(resp.StatusCode),
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Url:        url,
+		cause:      cause,
+	}
+
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, apiErrorBodyLimit))
+		apiErr.Code, apiErr.Message, apiErr.RequestId = parseAPIErrorBody(body)
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	for _, header := range requestIdHeaders {
+		if apiErr.RequestId != "" {
+			break
+		}
+		apiErr.RequestId = resp.Header.Get(header)
+	}
+	return apiErr
+}
+
+// newReportFailedError function builds an `APIError` for a learning activity report that did not complete.
+// It implements the error returned when polling finds a report in a terminal, unsuccessful state.
+// The function records the status and any error message reported by Percipio for the report.
+// Which distinguishes a failed report job from a failed HTTP request.
+// This implementation leaves the HTTP fields empty because the poll itself succeeded.
+func newReportFailedError(reportStatus ReportStatus) *APIError {
+	message := fmt.Sprintf("report generation failed with status: %s", reportStatus.Status)
+	if reportStatus.Error != "" {
+		message = fmt.Sprintf("%s: %s", message, reportStatus.Error)
+	}
+	return &APIError{
+		Kind:    ErrorKindReportFailed,
+		Code:    reportStatus.Status,
+		Message: message,
+	}
+}
+
+// parseAPIErrorBody function extracts the error code, message and request ID from an error response body.
+// It implements lenient decoding of the different error document shapes returned by Percipio.
+// The function prefers top-level fields and falls back to the first entry of an `errors` list.
+// Which surfaces Percipio's own explanation of a failure instead of only the HTTP status.
+// This implementation returns empty strings when the body is empty or not JSON.
+func parseAPIErrorBody(body []byte) (string, string, string) {
+	var parsed apiErrorBody
+	if len(body) == 0 || json.Unmarshal(body, &parsed) != nil {
+		return "", "", ""
+	}
+
+	code := parsed.ErrorCode
+	if code == "" && parsed.Code != nil {
+		code = fmt.Sprint(parsed.Code)
+	}
+	if code == "" {
+		code = parsed.Error
+	}
+
+	message := parsed.Message
+	if message == "" {
+		message = parsed.ErrorDescription
+	}
+
+	if len(parsed.Errors) > 0 {
+		if code == "" && parsed.Errors[0].Code != nil {
+			code = fmt.Sprint(parsed.Errors[0].Code)
+		}
+		if message == "" {
+			message = parsed.Errors[0].Message
+		}
+	}
+	return code, message, parsed.RequestId
+}
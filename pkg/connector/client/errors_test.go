@@ -0,0 +1,154 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyStatusCode(t *testing.T) {
+	for _, testCase := range []struct {
+		statusCode   int
+		expectedKind ErrorKind
+		expectedCode codes.Code
+		retryable    bool
+	}{
+		{http.StatusBadRequest, ErrorKindRequest, codes.InvalidArgument, false},
+		{http.StatusUnauthorized, ErrorKindAuthentication, codes.Unauthenticated, false},
+		{http.StatusForbidden, ErrorKindPermission, codes.PermissionDenied, false},
+		{http.StatusNotFound, ErrorKindNotFound, codes.NotFound, false},
+		{http.StatusMethodNotAllowed, ErrorKindRequest, codes.InvalidArgument, false},
+		{http.StatusRequestTimeout, ErrorKindServer, codes.Unavailable, true},
+		{http.StatusConflict, ErrorKindRequest, codes.AlreadyExists, false},
+		{http.StatusGone, ErrorKindNotFound, codes.NotFound, false},
+		{http.StatusUnprocessableEntity, ErrorKindRequest, codes.InvalidArgument, false},
+		{http.StatusTooManyRequests, ErrorKindRateLimit, codes.Unavailable, true},
+		{http.StatusInternalServerError, ErrorKindServer, codes.Unavailable, true},
+		{http.StatusBadGateway, ErrorKindServer, codes.Unavailable, true},
+		{http.StatusServiceUnavailable, ErrorKindServer, codes.Unavailable, true},
+		{http.StatusGatewayTimeout, ErrorKindServer, codes.Unavailable, true},
+	} {
+		t.Run(http.StatusText(testCase.statusCode), func(t *testing.T) {
+			kind := classifyStatusCode(testCase.statusCode)
+			require.Equal(t, testCase.expectedKind, kind)
+
+			apiErr := &APIError{Kind: kind, StatusCode: testCase.statusCode}
+			require.Equal(t, testCase.expectedCode, apiErr.GRPCCode())
+			require.Equal(t, testCase.expectedCode, status.Code(apiErr))
+			require.Equal(t, testCase.retryable, apiErr.Retryable())
+		})
+	}
+}
+
+func TestAPIErrorGRPCCode(t *testing.T) {
+	t.Run("should abort a failed report", func(t *testing.T) {
+		apiErr := newReportFailedError(ReportStatus{Status: "FAILED", Error: "report generation failed"})
+		require.Equal(t, ErrorKindReportFailed, apiErr.Kind)
+		require.Equal(t, "FAILED", apiErr.Code)
+		require.Equal(t, codes.Aborted, apiErr.GRPCCode())
+		require.False(t, apiErr.Retryable())
+		require.Contains(t, apiErr.Error(), "report generation failed with status: FAILED: report generation failed")
+	})
+
+	t.Run("should not classify an unknown kind", func(t *testing.T) {
+		require.Equal(t, codes.Unknown, (&APIError{}).GRPCCode())
+	})
+}
+
+func TestNewAPIError(t *testing.T) {
+	for _, testCase := range []struct {
+		name              string
+		statusCode        int
+		header            http.Header
+		body              string
+		expectedCode      string
+		expectedMessage   string
+		expectedRequestId string
+	}{
+		{
+			name:            "empty body",
+			statusCode:      http.StatusBadGateway,
+			body:            ``,
+			expectedMessage: "Bad Gateway",
+		},
+		{
+			name:            "malformed body",
+			statusCode:      http.StatusBadRequest,
+			body:            `{"code":"INVALID`,
+			expectedMessage: "Bad Request",
+		},
+		{
+			name:            "html body",
+			statusCode:      http.StatusServiceUnavailable,
+			body:            `<html><body>Service Unavailable</body></html>`,
+			expectedMessage: "Service Unavailable",
+		},
+		{
+			name:              "top-level fields",
+			statusCode:        http.StatusConflict,
+			body:              `{"errorCode":"USER_HAS_ACTIVITY","message":"user has learning activity","requestId":"request-1"}`,
+			expectedCode:      "USER_HAS_ACTIVITY",
+			expectedMessage:   "user has learning activity",
+			expectedRequestId: "request-1",
+		},
+		{
+			name:            "numeric code",
+			statusCode:      http.StatusBadRequest,
+			body:            `{"code":1001,"message":"invalid offset"}`,
+			expectedCode:    "1001",
+			expectedMessage: "invalid offset",
+		},
+		{
+			name:            "oauth2 fields",
+			statusCode:      http.StatusUnauthorized,
+			body:            `{"error":"invalid_client","error_description":"client authentication failed"}`,
+			expectedCode:    "invalid_client",
+			expectedMessage: "client authentication failed",
+		},
+		{
+			name:            "nested errors",
+			statusCode:      http.StatusUnprocessableEntity,
+			body:            `{"errors":[{"code":"EMAIL_TAKEN","message":"email already in use"},{"code":"OTHER","message":"ignored"}]}`,
+			expectedCode:    "EMAIL_TAKEN",
+			expectedMessage: "email already in use",
+		},
+		{
+			name:              "request id header",
+			statusCode:        http.StatusForbidden,
+			header:            http.Header{"X-Correlation-Id": []string{"correlation-1"}},
+			body:              `{"message":"forbidden"}`,
+			expectedMessage:   "forbidden",
+			expectedRequestId: "correlation-1",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			header := testCase.header
+			if header == nil {
+				header = http.Header{}
+			}
+			apiErr := newAPIError(&http.Response{
+				StatusCode: testCase.statusCode,
+				Header:     header,
+				Body:       io.NopCloser(strings.NewReader(testCase.body)),
+			}, http.MethodGet, "https://api.percipio.com/test", nil)
+
+			require.Equal(t, classifyStatusCode(testCase.statusCode), apiErr.Kind)
+			require.Equal(t, testCase.statusCode, apiErr.StatusCode)
+			require.Equal(t, testCase.expectedCode, apiErr.Code)
+			require.Equal(t, testCase.expectedMessage, apiErr.Message)
+			require.Equal(t, testCase.expectedRequestId, apiErr.RequestId)
+			require.Contains(t, apiErr.Error(), "GET https://api.percipio.com/test")
+		})
+	}
+
+	t.Run("should survive a response without a body", func(t *testing.T) {
+		apiErr := newAPIError(&http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}}, http.MethodDelete, "", nil)
+		require.Equal(t, ErrorKindNotFound, apiErr.Kind)
+		require.Equal(t, "Not Found", apiErr.Message)
+	})
+}
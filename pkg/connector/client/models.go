@@ -40,6 +40,29 @@ type Associations struct {
 	TranslationGroupId string             `json:"translationGroupId"`
 }
 
+// Audience struct represents a Percipio audience, a named group of users.
+// It is used by the audience resource syncer to create group resources.
+// It holds fields such as `Id`, `Name`, and `Description`.
+// This structure organizes the identity of the populations admins assign content to.
+// Instances are populated from the Percipio API response for audience data.
+type Audience struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Assignment struct represents a Percipio content assignment, content an admin has made mandatory for a user.
+// It is used by the course resource syncer to sync and provision the 'assigned' entitlement.
+// It holds fields such as `Id`, `ContentId`, `UserId`, and the optional `DueDate`.
+// This structure organizes assignment data for both the list and create endpoints.
+// Instances are populated from the Percipio API response for assignment data, or built by `CreateAssignment`.
+type Assignment struct {
+	Id        string `json:"id,omitempty"`
+	ContentId string `json:"contentId"`
+	UserId    string `json:"userId"`
+	DueDate   string `json:"dueDate,omitempty"`
+}
+
 // Channel struct represents a learning channel.
 // It is used by the `Associations` struct.
 // It holds fields such as `Id`, `Link`, and `Title`.
@@ -181,6 +204,16 @@ type LocalizedMetadata struct {
 	Title       string `json:"title"`
 }
 
+// Organization struct represents the Percipio organization (tenant) being synced.
+// It is used by the organization resource syncer to name the root of the resource tree.
+// It holds fields such as `Id` and `Name`.
+// This structure organizes the identity of the tenant so multi-tenant syncs stay unambiguous.
+// Instances are populated from the Percipio API response for organization data.
+type Organization struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
 // Parent struct represents the parent content item in a hierarchy.
 // It is used by the `Associations` struct.
 // It holds fields such as `Id`, `Title`, and `Type`.
@@ -292,6 +325,7 @@ type ReportEntry struct {
 	FirstAccess          time.Time `json:"firstAccess"`
 	FirstName            string    `json:"firstName"`
 	Geo                  string    `json:"geo"`
+	HighScore            string    `json:"highScore"`
 	HireDate             string    `json:"hireDate"`
 	HrbpOwner            string    `json:"hrbpOwner"`
 	IsAManager           string    `json:"isAManager"`
@@ -299,6 +333,7 @@ type ReportEntry struct {
 	LastName             string    `json:"lastName"`
 	ManagerEmail         string    `json:"managerEmail"`
 	ManagerId            string    `json:"managerId"`
+	PassingScore         string    `json:"passingScore"`
 	Status               string    `json:"status"`
 	UserId               string    `json:"userId"`
 	UserStatus           string    `json:"userStatus"`
@@ -346,6 +381,42 @@ type Technology struct {
 	Version string `json:"version"`
 }
 
+// CustomAttributeValue struct represents the value of a custom user attribute sent to Percipio.
+// It is used by the `UserRequest` struct.
+// It holds the attribute `Id` and its `Value`.
+// This structure organizes custom user data in the shape the user-management API accepts on write.
+// Instances are created by the connector from the account creation profile.
+type CustomAttributeValue struct {
+	Id    string `json:"id"`
+	Value string `json:"value"`
+}
+
+// UserRequest struct represents the body of a user creation request.
+// It is used by `CreateUser` to create a learner through the user-management API.
+// It holds fields such as `FirstName`, `LastName`, `Email`, `LoginName`, `Role`, and `CustomAttributes`.
+// This structure organizes the writable subset of the `User` fields.
+// Instances are created by the connector from the account creation profile.
+type UserRequest struct {
+	FirstName        string                 `json:"firstName"`
+	LastName         string                 `json:"lastName"`
+	Email            string                 `json:"email"`
+	LoginName        string                 `json:"loginName"`
+	Role             string                 `json:"role,omitempty"`
+	ExternalUserId   string                 `json:"externalUserId,omitempty"`
+	IsActive         bool                   `json:"isActive"`
+	CustomAttributes []CustomAttributeValue `json:"customAttributes,omitempty"`
+}
+
+// UserPatch struct represents the body of a user update request.
+// It is used by `UpdateUser` to change individual fields of an existing user.
+// It holds the optional `IsActive` status and `Role`.
+// This structure organizes partial updates so that unset fields are left untouched by Percipio.
+// Instances are created by the connector's user actions and role provisioning.
+type UserPatch struct {
+	IsActive *bool  `json:"isActive,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
 // User struct represents a single user identity in Percipio.
 // It is the primary data representation for user principals synced by the connector.
 // It holds fields such as `Id`, `Email`, `LoginName`, and `Role`.
@@ -1,7 +1,7 @@
 package client
 
 import (
-	"bytes"
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -9,11 +9,15 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/conductorone/baton-percipio/pkg/config"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/ratelimit"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
@@ -25,6 +29,12 @@ const (
 	ApiPathLearningActivityReport = "/reporting/v1/organizations/%s/report-requests/learning-activity"
 	ApiPathReport                 = "/reporting/v1/organizations/%s/report-requests/%s"
 	ApiPathUsersList              = "/user-management/v1/organizations/%s/users"
+	ApiPathUser                   = "/user-management/v1/organizations/%s/users/%s"
+	ApiPathOrganization           = "/user-management/v1/organizations/%s"
+	ApiPathAudiencesList          = "/user-management/v1/organizations/%s/audiences"
+	ApiPathAudienceUsersList      = "/user-management/v1/organizations/%s/audiences/%s/users"
+	ApiPathAssignments            = "/content-assignment/v1/organizations/%s/assignments"
+	ApiPathAssignment             = "/content-assignment/v1/organizations/%s/assignments/%s"
 	BaseApiUrl                    = "https://api.percipio.com"
 	HeaderNamePagingRequestId     = "x-paging-request-id"
 	HeaderNameTotalCount          = "x-total-count"
@@ -32,30 +42,90 @@ const (
 	ReportLookBackDefault         = 10 * time.Hour * 24 * 365 // 10 years
 )
 
+// regionBaseUrls maps each supported Percipio data center to its API base URL.
+// It is used by `ResolveBaseUrl` to translate the `percipio-region` configuration value.
+// It holds one entry per region constant declared in the config package.
+// This variable centralizes the data center endpoints so the same binary can serve every tenant.
+// The instance is a fixed lookup table and is never mutated.
+var regionBaseUrls = map[string]string{
+	config.RegionUS: BaseApiUrl,
+	config.RegionEU: "https://dew1-api.percipio.com",
+	config.RegionCA: "https://cac1-api.percipio.com",
+}
+
+// ResolveBaseUrl function determines the Percipio API base URL for the connector.
+// It implements the region and custom endpoint selection required to sync tenants outside the US data center.
+// The function prefers an explicit base URL, then falls back to the URL of the configured region, and finally to `BaseApiUrl`.
+// Which allows operators to target EU or Canadian tenants, or a local stand-in server for testing, without rebuilding the connector.
+// This implementation rejects unknown regions and base URLs that are not absolute http(s) URLs.
+func ResolveBaseUrl(region string, baseUrl string) (string, error) {
+	if baseUrl != "" {
+		parsedUrl, err := url.Parse(baseUrl)
+		if err != nil {
+			return "", fmt.Errorf("invalid percipio base url %q: %w", baseUrl, err)
+		}
+		if (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+			return "", fmt.Errorf("invalid percipio base url %q: must be an absolute http or https url", baseUrl)
+		}
+		return strings.TrimSuffix(baseUrl, "/"), nil
+	}
+
+	if region == "" {
+		return BaseApiUrl, nil
+	}
+
+	regionUrl, ok := regionBaseUrls[strings.ToLower(region)]
+	if !ok {
+		return "", fmt.Errorf("unknown percipio region %q, expected one of %v", region, config.Regions)
+	}
+	return regionUrl, nil
+}
+
 // Client struct manages all communication with the Percipio API.
 // It is used by the connector to abstract away the details of HTTP requests and response handling.
-// It holds fields such as baseUrl, bearerToken, and organizationId for authenticating and targeting API calls.
+// It holds fields such as baseUrl, tokenSource, and organizationId for authenticating and targeting API calls.
 // This structure organizes API client configuration and stateful data like ReportStatus for multi-step report generation.
 // Instances are typically created by the New function and populated with configuration from the connector.
 type Client struct {
-	baseUrl        *url.URL
-	bearerToken    string
-	StatusesStore  StatusesStore
-	organizationId string
-	ReportStatus   ReportStatus
-	wrapper        *uhttp.BaseHttpClient
+	baseUrl          *url.URL
+	httpClient       *http.Client
+	tokenSource      tokenSource
+	StatusesStore    StatusesStore
+	organizationId   string
+	ReportStatus     ReportStatus
+	reportJobs       *ReportJobStore
+	reportJob        ReportJob
+	reportResumed    bool
+	incrementalSync  bool
+	fullSyncInterval time.Duration
+	reportPolling    ReportPolling
+	reportSlicing    ReportSlicing
+	rateLimiter      *rateLimiter
+	maxRetries       int
+	reportLookback   time.Duration
+	reportStartDate  time.Time
+	contentTypes     []string
+	reportAudiences  []string
+	attributeGroups  []AttributeGroupMapping
+	reportUserFields *reportUserFields
+	assignmentDueIn  time.Duration
+	defaultRole      string
+	userDeletion     bool
+	wrapper          *uhttp.BaseHttpClient
 }
 
 // New function creates and initializes a new Percipio API Client.
 // It implements the instantiation of the API client required by the connector to interact with the Percipio API.
 // The client is created by configuring a `uhttp.Client` from the baton-sdk, parsing the provided base URL, and populating the Client struct with authentication details.
 // Which provides a centralized and consistent method for creating a ready-to-use API client.
-// This implementation aligns with SDK patterns by using `uhttp.NewClient` for robust, logged HTTP communication.
+// This implementation aligns with SDK patterns by using `uhttp.NewClient` for robust, logged HTTP communication,
+// and applies any `Option` values (such as `WithClientCredentials`) after the defaults are set, rejecting combinations of options that cannot work together.
 func New(
 	ctx context.Context,
 	baseUrl string,
 	organizationId string,
 	token string,
+	opts ...Option,
 ) (*Client, error) {
 	httpClient, err := uhttp.NewClient(
 		ctx,
@@ -78,13 +148,40 @@ func New(
 		return nil, err
 	}
 
-	return &Client{
-		StatusesStore:  make(map[string]map[string]string),
+	client := &Client{
+		StatusesStore:  NewMemoryStatusesStore(),
 		baseUrl:        parsedUrl,
-		bearerToken:    token,
+		httpClient:     httpClient,
+		tokenSource:    staticTokenSource(token),
 		organizationId: organizationId,
+		reportPolling:  defaultReportPolling(),
+		reportSlicing:  defaultReportSlicing(),
+		rateLimiter:    defaultRateLimiter(),
+		maxRetries:     config.MaxRetriesDefault,
+		reportLookback: ReportLookBackDefault,
+		contentTypes:   defaultContentTypes(),
+		defaultRole:    config.DefaultRoleDefault,
 		wrapper:        wrapper,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if err := client.validateAttributeGroups(); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// OrganizationId method returns the Percipio organization the client is scoped to.
+// It implements the lookup used by the connector to identify the tenant as a resource.
+// The method returns the organization ID the client was created with.
+// Which gives tenant-level resources a stable ID without another API call.
+// This implementation returns the value unchanged.
+func (c *Client) OrganizationId() string {
+	return c.organizationId
 }
 
 // getTotalCount function extracts the total result count from an HTTP response.
@@ -131,6 +228,198 @@ func (c *Client) GetUsers(
 	return target, total, ratelimitData, nil
 }
 
+// CreateUser method creates a user in Percipio.
+// It implements the account provisioning operation required by the user resource syncer.
+// The method sends a POST request with the user's details to the `ApiPathUsersList` endpoint.
+// Which lets learners be onboarded without a Percipio admin creating them by hand.
+// This implementation returns the user as created by Percipio, including its generated ID.
+func (c *Client) CreateUser(
+	ctx context.Context,
+	user UserRequest,
+) (
+	*User,
+	*v2.RateLimitDescription,
+	error,
+) {
+	var target User
+	response, ratelimitData, err := c.post(ctx, ApiPathUsersList, user, &target)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	return &target, ratelimitData, nil
+}
+
+// userPath function builds the API path of a single user.
+// It implements the path construction shared by the user operations that target one user.
+// The function escapes the user ID before it is combined with the organization ID by `getUrl`.
+// Which keeps IDs containing reserved characters from changing the request path.
+// This implementation mirrors the escaping used by `GetAudienceUsers`.
+func userPath(userId string) string {
+	return fmt.Sprintf(ApiPathUser, "%s", strings.ReplaceAll(url.PathEscape(userId), "%", "%%"))
+}
+
+// GetUser method fetches a single user from the Percipio API.
+// It implements the lookup used by user actions to check a user's current state before changing it.
+// The method calls the internal `get` helper against the `ApiPathUser` endpoint.
+// Which lets repeated workflows skip updates that have already been applied.
+// This implementation returns the typed API error unchanged, so an unknown user surfaces as `NotFound`.
+func (c *Client) GetUser(
+	ctx context.Context,
+	userId string,
+) (
+	*User,
+	*v2.RateLimitDescription,
+	error,
+) {
+	var target User
+	response, ratelimitData, err := c.get(ctx, userPath(userId), nil, &target)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	return &target, ratelimitData, nil
+}
+
+// UpdateUser method changes fields of an existing Percipio user.
+// It implements the user update operation required by user actions and role provisioning.
+// The method sends a PATCH request with only the fields set on `patch` to the `ApiPathUser` endpoint.
+// Which changes a user's status or role without overwriting the rest of the profile.
+// This implementation returns the user as updated by Percipio.
+func (c *Client) UpdateUser(
+	ctx context.Context,
+	userId string,
+	patch UserPatch,
+) (
+	*User,
+	*v2.RateLimitDescription,
+	error,
+) {
+	var target User
+	response, ratelimitData, err := c.patch(ctx, userPath(userId), patch, &target)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	return &target, ratelimitData, nil
+}
+
+// DeleteUser method removes a user from Percipio.
+// It implements the deletion operation behind the user resource deleter.
+// The method sends a DELETE request for the user to the `ApiPathUser` endpoint.
+// Which lets offboarding remove users who never started any learning.
+// This implementation returns the typed API error unchanged so callers can tell a refused deletion from a missing user.
+func (c *Client) DeleteUser(
+	ctx context.Context,
+	userId string,
+) (
+	*v2.RateLimitDescription,
+	error,
+) {
+	response, ratelimitData, err := c.delete(ctx, userPath(userId))
+	if err != nil {
+		return ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	return ratelimitData, nil
+}
+
+// GetOrganization method fetches the details of the configured organization from the Percipio API.
+// It implements the lookup used to name the organization resource at the root of the resource tree.
+// The method calls the internal `get` helper against the `ApiPathOrganization` endpoint.
+// Which enriches the bare organization ID with a human-readable name.
+// This implementation returns the typed API error unchanged so callers can tolerate tenants where the endpoint is unavailable.
+func (c *Client) GetOrganization(
+	ctx context.Context,
+) (
+	*Organization,
+	*v2.RateLimitDescription,
+	error,
+) {
+	var target Organization
+	response, ratelimitData, err := c.get(ctx, ApiPathOrganization, nil, &target)
+	if err != nil {
+		return nil, ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	return &target, ratelimitData, nil
+}
+
+// GetAudiences method fetches a single page of audiences from the Percipio API.
+// It implements the audience data retrieval operation required by the audience resource syncer.
+// The method builds a query with offset and limit parameters and calls the internal `get` helper
+// to execute the request against the `ApiPathAudiencesList` endpoint.
+// Which enables the connector to paginate through the audiences admins use to assign content.
+// This implementation shares the offset pagination and `x-total-count` handling of `GetUsers`.
+func (c *Client) GetAudiences(
+	ctx context.Context,
+	offset int,
+	limit int,
+) (
+	[]Audience,
+	int,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := map[string]interface{}{
+		"max":    limit,
+		"offset": offset,
+	}
+	var target []Audience
+	response, ratelimitData, err := c.get(ctx, ApiPathAudiencesList, query, &target)
+	if err != nil {
+		return nil, 0, ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	total, err := getTotalCount(response)
+	if err != nil {
+		return nil, 0, ratelimitData, err
+	}
+	return target, total, ratelimitData, nil
+}
+
+// GetAudienceUsers method fetches a single page of the users in an audience.
+// It implements the membership retrieval operation required by the audience grant builder.
+// The method calls the internal `get` helper against the `ApiPathAudienceUsersList` endpoint for the given audience.
+// Which enables the connector to paginate through an audience's members without listing every user in the tenant.
+// This implementation escapes the audience ID before it is combined with the organization ID.
+func (c *Client) GetAudienceUsers(
+	ctx context.Context,
+	audienceId string,
+	offset int,
+	limit int,
+) (
+	[]User,
+	int,
+	*v2.RateLimitDescription,
+	error,
+) {
+	query := map[string]interface{}{
+		"max":    limit,
+		"offset": offset,
+	}
+	path := fmt.Sprintf(ApiPathAudienceUsersList, "%s", strings.ReplaceAll(url.PathEscape(audienceId), "%", "%%"))
+
+	var target []User
+	response, ratelimitData, err := c.get(ctx, path, query, &target)
+	if err != nil {
+		return nil, 0, ratelimitData, err
+	}
+	defer response.Body.Close()
+
+	total, err := getTotalCount(response)
+	if err != nil {
+		return nil, 0, ratelimitData, err
+	}
+	return target, total, ratelimitData, nil
+}
+
 // GetCourses method fetches a single page of course resources using Percipio's specialized catalog pagination.
 // It implements the content data retrieval operation required by the course resource syncer for a full sync.
 // The method manages a stateful pagination flow by sending an `offset` and `limit`, and then using a `pagingRequestId` returned in the first response for all subsequent requests.
@@ -182,9 +471,9 @@ func (c *Client) GetCourses(
 	return target, newPagingRequestId, finalOffset, ratelimitData, nil
 }
 
-// SearchContentByID function searches for a single course or assessment by its unique ID.
+// SearchContentByID function searches for a single content item by its unique ID.
 // It implements a more targeted content retrieval method required for the limited-courses sync feature.
-// The function constructs a GET request to the `/search-content` endpoint using the content ID as a query and filters for COURSE and ASSESSMENT types.
+// The function constructs a GET request to the `/search-content` endpoint using the content ID as a query and filters for the configured content types.
 // Which provides an efficient way to fetch specific content items without paginating through the entire catalog.
 // This implementation makes a separate API call for each ID because the Percipio search API may return unexpected or overly broad results if more than one ID is queried at a time.
 func (c *Client) SearchContentByID(
@@ -197,7 +486,7 @@ func (c *Client) SearchContentByID(
 ) {
 	query := map[string]interface{}{
 		"q":          courseID,
-		"typeFilter": "COURSE,ASSESSMENT",
+		"typeFilter": strings.Join(c.PercipioContentTypes(), ","),
 	}
 
 	var target []Course
@@ -212,9 +501,12 @@ func (c *Client) SearchContentByID(
 
 // GenerateLearningActivityReport method initiates the creation of a learning activity report.
 // It implements the first step of the asynchronous report generation process required by the connector to fetch grants.
-// The method sends a POST request to the `ApiPathLearningActivityReport` endpoint with a lookback period, which triggers a background job on the Percipio service.
+// The method sends a POST request to the `ApiPathLearningActivityReport` endpoint with a lookback period, or only the delta since the last watermark in incremental mode,
+// which triggers a background job on the Percipio service.
 // Which is the only way the connector can access data about user course assignments, completions, and progress.
-// This implementation stores the returned report ID in the `c.ReportStatus` field, which is essential for the subsequent polling step.
+// This implementation stores the returned report ID in the `c.ReportStatus` field, which is essential for the subsequent polling step,
+// and persists it to the report job store, when configured, so a resumed sync can pick it up.
+// Full reports are split into `ReportSlicing.Count` time slices that are requested concurrently.
 func (c *Client) GenerateLearningActivityReport(
 	ctx context.Context,
 ) (
@@ -222,107 +514,334 @@ func (c *Client) GenerateLearningActivityReport(
 	error,
 ) {
 	now := time.Now()
-	body := ReportConfigurations{
-		End:         now,
-		Start:       now.Add(-ReportLookBackDefault),
-		ContentType: "Course,Assessment",
+	start, incremental := c.reportWindowStart(ctx, now)
+	sliceCount := c.reportSlicing.Count
+	if incremental {
+		sliceCount = 1
 	}
 
-	var target ReportStatus
-	response, ratelimitData, err := c.post(
-		ctx,
-		ApiPathLearningActivityReport,
-		body,
-		&target,
-	)
+	parts := splitReportWindow(start, now, sliceCount)
+	ratelimitData, err := c.requestReportSlices(ctx, parts)
 	if err != nil {
 		return ratelimitData, err
 	}
-	defer response.Body.Close()
 
-	c.ReportStatus = target
+	c.ReportStatus = ReportStatus{
+		Id:     parts[0].Id,
+		Status: parts[0].Status,
+	}
+	c.reportResumed = false
+	c.reportJob = ReportJob{
+		Id:          parts[0].Id,
+		Status:      parts[0].Status,
+		Start:       start,
+		End:         now,
+		RequestedAt: now,
+		Incremental: incremental,
+	}
+	if len(parts) > 1 {
+		c.reportJob.Slices = parts
+	}
+
+	if c.reportJobs != nil {
+		err = c.reportJobs.Save(c.reportJob)
+		if err != nil {
+			ctxzap.Extract(ctx).Warn("failed to persist learning activity report job", zap.Error(err))
+		}
+	}
 
 	return ratelimitData, nil
 }
 
-// pollLearningActivityReport method polls a report URL until the report is successfully generated.
-// The function makes repeated GET requests to the report URL until the status is no longer "IN_PROGRESS".
+// ResumeLearningActivityReport method restores an in-flight learning activity report from the report job store.
+// It implements the resume step that replaces `GenerateLearningActivityReport` after a checkpoint or restart.
+// The method loads the persisted job and, if it is recent and still pending, sets `c.ReportStatus` so the next poll reuses it.
+// Which avoids kicking off a brand new multi-hour report when a sync is resumed.
+// This implementation returns false, clearing any stale or unreadable state, when there is nothing to resume.
+func (c *Client) ResumeLearningActivityReport(ctx context.Context) bool {
+	if c.reportJobs == nil {
+		return false
+	}
+
+	l := ctxzap.Extract(ctx)
+	job, err := c.reportJobs.Load()
+	if err != nil {
+		l.Warn("failed to load learning activity report job, a new report will be generated", zap.Error(err))
+		c.clearReportJob(ctx)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	if slices.ContainsFunc(job.reportSlices(), func(part ReportSlice) bool { return part.Id == "" }) ||
+		(job.Status != "PENDING" && job.Status != "IN_PROGRESS") ||
+		time.Since(job.RequestedAt) > ReportJobMaxAge {
+		l.Debug("discarding persisted learning activity report job",
+			zap.String("report_id", job.Id),
+			zap.Int("slices", len(job.Slices)),
+			zap.String("status", job.Status),
+			zap.Time("requested_at", job.RequestedAt),
+		)
+		c.clearReportJob(ctx)
+		return false
+	}
+
+	l.Info("resuming learning activity report",
+		zap.String("report_id", job.Id),
+		zap.Int("slices", len(job.Slices)),
+		zap.Time("requested_at", job.RequestedAt),
+	)
+	c.ReportStatus = ReportStatus{
+		Id:     job.Id,
+		Status: job.Status,
+	}
+	c.reportJob = *job
+	c.reportResumed = true
+	return true
+}
+
+// clearReportJob method removes the persisted report job, if any.
+// It implements the cleanup performed once a report has been consumed or has failed.
+// The method delegates to the report job store and logs failures instead of returning them.
+// Which keeps a stale job from being resumed by a later sync.
+// This implementation is a no-op when durable tracking is disabled.
+func (c *Client) clearReportJob(ctx context.Context) {
+	if c.reportJobs == nil {
+		return
+	}
+	err := c.reportJobs.Clear()
+	if err != nil {
+		ctxzap.Extract(ctx).Warn("failed to clear learning activity report job", zap.Error(err))
+	}
+}
+
+// pollLearningActivityReport method polls a single report until it is successfully generated.
+// The function makes repeated GET requests to the report URL until the status is no longer "IN_PROGRESS",
+// waiting between attempts with jittered exponential backoff bounded by the client's `ReportPolling` settings.
 // Which is necessary because the initial report generation request only returns a job ID, not the final data.
-// This implementation includes a custom retry loop and handles the API's unusual behavior
-// of returning different data structures for the same endpoint.
+// This implementation honors context cancellation and the configured overall timeout, logs progress on every attempt,
+// keeps waiting through retryable `APIError` responses, and hands the still-open report body to `consume` so the rows can be streamed rather than buffered.
+// The timeout only bounds waiting for the report to be ready; streaming a finished report is bounded by `ctx` alone.
 // We use the native Go net/http package instead of uhttp for the report polling function as uhttp
 // seems to ignore Cache-Control: no-cache headers and kept returning IN_PROGRESS for the report polling
 // even when the report was completed and available during testing.
-func (c *Client) pollLearningActivityReport(ctx context.Context, reportUrl string) ([]byte, *v2.RateLimitDescription, error) {
+func (c *Client) pollLearningActivityReport(
+	ctx context.Context,
+	reportId string,
+	consume func(io.Reader) error,
+) (*v2.RateLimitDescription, error) {
 	var ratelimitData *v2.RateLimitDescription
+	reportUrl := fmt.Sprintf("%s%s", c.baseUrl.String(), fmt.Sprintf(ApiPathReport, c.organizationId, reportId))
 
 	l := ctxzap.Extract(ctx)
-	for i := 0; i < config.RetryAttemptsMaximum; i++ {
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reportUrl, nil)
+	pollCtx, cancel := context.WithTimeout(ctx, c.reportPolling.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	interval := c.reportPolling.InitialInterval
+	for attempt := 1; ; attempt++ {
+		resp, release, err := c.openReport(ctx, pollCtx, reportUrl)
 		if err != nil {
-			return nil, nil, err
+			if pollCtx.Err() != nil && ctx.Err() == nil {
+				return ratelimitData, fmt.Errorf("report polling timed out after %s: %w", time.Since(start).Round(time.Second), err)
+			}
+			return ratelimitData, err
+		}
+		if responseRatelimitData, err := ratelimit.ExtractRateLimitData(resp.StatusCode, &resp.Header); err == nil {
+			ratelimitData = responseRatelimitData
 		}
 
-		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
-		req.Header.Set("Content-Type", "application/json")
+		var status string
+		var done bool
+		if resp.StatusCode >= http.StatusBadRequest {
+			apiErr := newAPIError(resp, http.MethodGet, reportUrl, nil)
+			_ = resp.Body.Close()
+			release()
+			if apiErr.Kind == ErrorKindNotFound {
+				return ratelimitData, fmt.Errorf("%w: %w", ErrReportExpired, apiErr)
+			}
+			if !apiErr.Retryable() {
+				return ratelimitData, apiErr
+			}
+			l.Warn("transient error while polling learning activity report, retrying", zap.Error(apiErr))
+			status = string(apiErr.Kind)
+		} else {
+			status, done, err = c.readReportResponse(ctx, resp, consume)
+			release()
+			if err != nil {
+				return ratelimitData, err
+			}
+		}
+		if done {
+			l.Info("learning activity report ready",
+				zap.String("report_id", reportId),
+				zap.Int("attempts", attempt),
+				zap.Duration("elapsed", time.Since(start)),
+			)
+			return ratelimitData, nil
+		}
 
-		resp, err := http.DefaultClient.Do(req)
+		wait := jitter(interval)
+		l.Info("waiting for learning activity report",
+			zap.String("report_id", reportId),
+			zap.String("status", status),
+			zap.Int("attempt", attempt),
+			zap.Duration("elapsed", time.Since(start)),
+			zap.Duration("next_poll_in", wait),
+			zap.Duration("timeout", c.reportPolling.Timeout),
+		)
+
+		err = sleepContext(pollCtx, wait)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ratelimitData, ctx.Err()
+			}
+			return ratelimitData, fmt.Errorf("report polling timed out after %s", time.Since(start).Round(time.Second))
+		}
+		interval = c.reportPolling.nextInterval(interval)
+	}
+}
+
+// readReportResponse method interprets a single report polling response.
+// It implements the handling of the API's unusual behavior of returning different data structures for the same endpoint.
+// The method peeks at the first non-whitespace byte: an array is the finished report and is passed to `consume`,
+// an object is a status document, and an empty body means the report is not ready yet.
+// Which keeps the polling loop free of response parsing details.
+// This implementation always closes the response body and returns whether the report was consumed.
+func (c *Client) readReportResponse(
+	ctx context.Context,
+	resp *http.Response,
+	consume func(io.Reader) error,
+) (string, bool, error) {
+	defer resp.Body.Close()
+
+	l := ctxzap.Extract(ctx)
+	body := bufio.NewReader(resp.Body)
+	first, err := peekFirstByte(body)
+	if err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			l.Warn("empty response body from percipio api, retrying...")
+			return "", false, nil
+		}
+		l.Error("error reading response body", zap.Error(err))
+		return "", false, err
+	}
+
+	switch first {
+	case '[':
+		err = consume(body)
+		if err != nil {
+			return "", false, err
+		}
+		return "", true, nil
+	case '{':
+		var reportStatus ReportStatus
+		err = json.NewDecoder(body).Decode(&reportStatus)
 		if err != nil {
-			return nil, nil, err
+			l.Error("error unmarshalling report status", zap.Error(err))
+			return "", false, fmt.Errorf("failed to unmarshal report status object: %w", err)
 		}
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
-			l.Error("error reading response body", zap.Error(err))
-			_ = resp.Body.Close()
-			return nil, ratelimitData, err
+		if reportStatus.Status != "PENDING" && reportStatus.Status != "IN_PROGRESS" {
+			return "", false, newReportFailedError(reportStatus)
 		}
+		return reportStatus.Status, false, nil
+	default:
+		return "", false, fmt.Errorf("unexpected report response format")
+	}
+}
+
+// openReport method issues a single report poll request whose wait for a response is bounded by the polling timeout.
+// It implements the scoping of the report polling timeout used by `pollLearningActivityReport`.
+// The method derives the request context from `ctx` and cancels it if `pollCtx` expires before `getReport` returns.
+// Which lets a report that became ready just before the timeout be streamed in full instead of being cut off mid-body.
+// This implementation returns a release function that the caller must call once the response body is closed,
+// and treats a response that races with the timeout as timed out.
+func (c *Client) openReport(ctx context.Context, pollCtx context.Context, reportUrl string) (*http.Response, func(), error) {
+	requestCtx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(pollCtx, cancel)
+	resp, err := c.getReport(requestCtx, reportUrl)
+	if !stop() && err == nil {
 		_ = resp.Body.Close()
+		err = pollCtx.Err()
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return resp, cancel, nil
+}
 
-		trimmedBody := bytes.TrimSpace(bodyBytes)
-		if len(trimmedBody) == 0 {
-			l.Warn("empty response body from percipio api, retrying...")
-			time.Sleep(time.Second * time.Duration(config.RetryAfterSeconds))
-			continue
+// getReport method issues a single authenticated GET request for a report URL using the native net/http client.
+// It implements the raw request behind `openReport`, used by `pollLearningActivityReport`, which cannot go through uhttp.
+// The method waits for the client's rate limiter, sets the bearer token from the client's token source and, when the API answers 401 and the token can be refreshed, retries once with a new token.
+// Responses with 429 or 503 are retried up to `maxRetries` times, honoring `Retry-After` and rate limit reset headers.
+// Which keeps report polling authenticated even when a service-account token expires mid-poll.
+// This implementation returns the open response; the caller is responsible for closing its body.
+func (c *Client) getReport(ctx context.Context, reportUrl string) (*http.Response, error) {
+	refreshed := false
+	retries := 0
+	for {
+		err := c.rateLimiter.Wait(ctx)
+		if err != nil {
+			return nil, err
 		}
 
-		if trimmedBody[0] == '[' {
-			return trimmedBody, ratelimitData, nil
+		token, err := c.tokenSource.Token(ctx)
+		if err != nil {
+			return nil, err
 		}
 
-		if trimmedBody[0] == '{' {
-			var reportStatus ReportStatus
-			err = json.Unmarshal(trimmedBody, &reportStatus)
-			if err != nil {
-				l.Error("error unmarshalling report status", zap.Error(err), zap.String("body", string(trimmedBody)))
-				return nil, ratelimitData, fmt.Errorf("failed to unmarshal report status object: %w", err)
-			}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reportUrl, nil)
+		if err != nil {
+			return nil, err
+		}
 
-			l.Debug("report status",
-				zap.String("status", reportStatus.Status),
-				zap.Int("attempt", i),
-				zap.Int("retry_after_seconds", config.RetryAfterSeconds),
-				zap.Int("retry_attempts_maximum", config.RetryAttemptsMaximum))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
 
-			if reportStatus.Status == "PENDING" || reportStatus.Status == "IN_PROGRESS" {
-				time.Sleep(time.Second * time.Duration(config.RetryAfterSeconds))
-				continue
-			}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
 
-			return nil, ratelimitData, fmt.Errorf("report generation failed with status: %s", reportStatus.Status)
+		if resp.StatusCode == http.StatusUnauthorized && !refreshed && c.tokenSource.Invalidate() {
+			_ = resp.Body.Close()
+			ctxzap.Extract(ctx).Debug("report poll returned 401, retrying with a refreshed token")
+			refreshed = true
+			continue
 		}
 
-		return nil, ratelimitData, fmt.Errorf("unexpected report response format")
-	}
+		if isThrottledStatus(http.MethodGet, resp.StatusCode) && retries < c.maxRetries {
+			_ = resp.Body.Close()
+			wait := retryDelay(resp, retries)
+			retries++
+			ctxzap.Extract(ctx).Warn("report poll was throttled, retrying",
+				zap.Int("status_code", resp.StatusCode),
+				zap.Int("retry", retries),
+				zap.Duration("wait", wait),
+			)
+			err = sleepContext(ctx, wait)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
 
-	return nil, ratelimitData, fmt.Errorf("report polling timed out")
+		return resp, nil
+	}
 }
 
 // GetLearningActivityReport method retrieves the completed learning activity report.
 // It implements the final step of the grant data retrieval process, required by the course grant builder.
-// The method first calls `pollLearningActivityReport` to wait for and receive the raw report data, then unmarshals it into a `Report` struct.
+// The method calls `pollLearningActivityReport` to wait for the report and decodes the response row by row with `DecodeReport`.
 // Which makes the complete set of user-course relationships available to the connector.
-// This implementation finishes the asynchronous workflow by loading the report data into the `StatusesStore` for efficient grant lookups.
+// This implementation streams each row directly into the `StatusesStore`, so peak memory follows the number of distinct
+// user/content pairs rather than the raw report size, and reports `ErrReportExpired` when the report ID is unknown or gone, or a resumed report has failed.
+// Any other error is returned unchanged and leaves the persisted report job in place, so the next attempt can resume the same report.
+// Sliced reports are polled concurrently and merged into the store one row at a time,
+// relying on the store's status precedence so the result does not depend on the order in which slices complete.
 func (c *Client) GetLearningActivityReport(
 	ctx context.Context,
 ) (
@@ -330,28 +849,69 @@ func (c *Client) GetLearningActivityReport(
 	error,
 ) {
 	var (
+		mu            sync.Mutex
+		prepared      bool
 		ratelimitData *v2.RateLimitDescription
-		target        Report
 	)
-	reportUrl := fmt.Sprintf("%s%s", c.baseUrl.String(), fmt.Sprintf(ApiPathReport, c.organizationId, c.ReportStatus.Id))
-	bodyBytes, ratelimitData, err := c.pollLearningActivityReport(ctx, reportUrl)
-	if err != nil {
-		return ratelimitData, err
-	}
-
 	l := ctxzap.Extract(ctx)
-	err = json.Unmarshal(bodyBytes, &target)
-	if err != nil {
-		l.Error("error unmarshalling learning activity report", zap.Error(err))
-		return ratelimitData, err
+	addRow := func(row *ReportEntry) error {
+		mu.Lock()
+		defer mu.Unlock()
+		c.reportUserFields.record(row)
+		return c.StatusesStore.Add(ctx, row)
 	}
 
-	c.ReportStatus.Status = "COMPLETED"
+	parts := c.reportJob.reportSlices()
+	err := forEachReportSlice(ctx, len(parts), c.reportSlicing.Concurrency, func(ctx context.Context, i int) error {
+		sliceRatelimitData, err := c.pollLearningActivityReport(ctx, parts[i].Id, func(body io.Reader) error {
+			mu.Lock()
+			if !prepared {
+				err := c.prepareReportLoad(ctx)
+				if err != nil {
+					mu.Unlock()
+					return err
+				}
+				prepared = true
+			}
+			mu.Unlock()
 
-	l.Debug("loading report")
-	err = c.StatusesStore.Load(&target)
+			l.Debug("loading report",
+				zap.String("report_id", parts[i].Id),
+				zap.Bool("incremental", c.reportJob.Incremental),
+			)
+			rows, err := DecodeReport(body, addRow)
+			if err != nil {
+				l.Error("error decoding learning activity report", zap.Error(err), zap.Int("rows", rows))
+				return err
+			}
+			l.Debug("loaded report", zap.String("report_id", parts[i].Id), zap.Int("rows", rows))
+			return nil
+		})
+
+		mu.Lock()
+		if sliceRatelimitData != nil {
+			ratelimitData = sliceRatelimitData
+		}
+		mu.Unlock()
+		return err
+	})
 	if err != nil {
+		if ctx.Err() != nil {
+			return ratelimitData, err
+		}
+		var apiErr *APIError
+		reportFailed := errors.As(err, &apiErr) && apiErr.Kind == ErrorKindReportFailed
+		if c.reportResumed && reportFailed {
+			err = fmt.Errorf("%w: %w", ErrReportExpired, err)
+		}
+		if reportFailed || errors.Is(err, ErrReportExpired) {
+			c.clearReportJob(ctx)
+		}
 		return ratelimitData, err
 	}
+	c.clearReportJob(ctx)
+	c.commitReportWindow(ctx)
+
+	c.ReportStatus.Status = "COMPLETED"
 	return ratelimitData, nil
 }
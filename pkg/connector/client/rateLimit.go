@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+	"github.com/conductorone/baton-sdk/pkg/ratelimit"
+)
+
+const (
+	retryBackoffInitial = time.Second
+	retryWaitMaximum    = 5 * time.Minute
+)
+
+// rateLimiter struct is a token bucket shared by every request the client sends.
+// It is used by `sendRequest` and `getReport` to keep the client under Percipio's request rate limits.
+// It holds the refill rate in requests per second, the bucket size, the tokens currently available and when they were last refilled.
+// This structure organizes client-side throttling so fan-out operations such as limited course searches do not trigger 429 responses.
+// Instances are created by the `WithRateLimit` option; a nil limiter does not throttle.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter function creates a token bucket limiter.
+// It implements the constructor for the client's `rateLimiter`.
+// The function starts with a full bucket so the first `burst` requests are sent immediately.
+// Which keeps short syncs of small tenants as fast as before.
+// This implementation raises a non-positive burst to one request.
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	size := float64(max(burst, 1))
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  size,
+		tokens: size,
+		last:   time.Now(),
+	}
+}
+
+// Wait method blocks until the limiter allows one more request.
+// It implements the throttling step performed before every outgoing Percipio request.
+// The method refills the bucket for the elapsed time, reserves a token and sleeps for as long as the reservation is in debt.
+// Which spreads concurrent callers evenly over time instead of letting them all retry at once.
+// This implementation returns the context error without reserving a token when the context is already done,
+// returns the context error if the wait is interrupted, and never blocks on a nil limiter.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	return sleepContext(ctx, wait)
+}
+
+// WithRateLimit function configures client-side throttling of Percipio requests.
+// It implements the option used by the connector to apply the rate limit configuration fields.
+// The function replaces the client's token bucket with one allowing `requestsPerSecond` on average and bursts of up to `burst` requests.
+// Which keeps fan-out operations below Percipio's rate limits before the API has to reject them.
+// This implementation disables throttling when `requestsPerSecond` is not positive.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.rateLimiter = nil
+			return
+		}
+		c.rateLimiter = newRateLimiter(requestsPerSecond, burst)
+	}
+}
+
+// WithMaxRetries function configures how often a throttled request is retried.
+// It implements the option used by the connector to apply the `max-retries` configuration field.
+// The function sets how many times a request answered with 429, or with 503 for idempotent methods, is repeated before the error is returned.
+// Which bounds how long a single call may wait on an overloaded Percipio tenant.
+// This implementation ignores negative values and keeps the default.
+func WithMaxRetries(maxRetries int) Option {
+	return func(c *Client) {
+		if maxRetries < 0 {
+			return
+		}
+		c.maxRetries = maxRetries
+	}
+}
+
+// defaultRateLimiter function returns the limiter used when none is configured.
+// It implements the fallback value for the client's throttling settings.
+// The function builds a token bucket from the defaults declared in the config package.
+// Which keeps the client polite towards the Percipio API without any configuration.
+// This implementation mirrors the default values of the corresponding configuration fields.
+func defaultRateLimiter() *rateLimiter {
+	return newRateLimiter(config.RateLimitRequestsPerSecondDefault, config.RateLimitBurstDefault)
+}
+
+// isThrottledStatus function reports whether a throttled request may be sent again.
+// It implements the retry condition shared by the uhttp and raw report-polling request paths.
+// The function matches 429 Too Many Requests for every method, and 503 Service Unavailable for the idempotent GET, PUT and DELETE methods.
+// Which are the responses Percipio pairs with `Retry-After` or rate limit reset headers, without replaying a write the server may already have applied.
+// This implementation does not retry any other status code.
+func isThrottledStatus(method string, statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusServiceUnavailable:
+		return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+	default:
+		return false
+	}
+}
+
+// retryDelay function computes how long to wait before retrying a throttled request.
+// It implements the `Retry-After` and rate limit reset handling shared by every request path.
+// The function honors a `Retry-After` header in seconds or as an HTTP date, then any rate limit reset header,
+// and otherwise backs off exponentially from `retryBackoffInitial`.
+// Which lets Percipio dictate the pace of retries when it tells the client how long to back off.
+// This implementation caps every delay at `retryWaitMaximum`.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	delay := retryBackoffInitial << min(attempt, 8)
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			delay = time.Duration(seconds) * time.Second
+		} else if at, err := http.ParseTime(retryAfter); err == nil {
+			delay = time.Until(at)
+		}
+	} else {
+		ratelimitData, err := ratelimit.ExtractRateLimitData(resp.StatusCode, &resp.Header)
+		if err == nil && ratelimitData != nil && ratelimitData.ResetAt != nil {
+			if untilReset := time.Until(ratelimitData.ResetAt.AsTime()); untilReset > 0 {
+				delay = untilReset
+			}
+		}
+	}
+
+	return min(max(delay, 0), retryWaitMaximum)
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestThrottledRetries(t *testing.T) {
+	ctx := context.Background()
+
+	newThrottledClient := func(t *testing.T, statusCode int, requests *atomic.Int32) *Client {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if requests.Add(1) == 1 {
+				writer.Header().Set("Retry-After", "0")
+				writer.WriteHeader(statusCode)
+				return
+			}
+			writer.Header().Set("Content-Type", "application/json")
+			_, _ = writer.Write([]byte(`{"id":"00000000-0000-0000-0000-000000000001"}`))
+		}))
+		t.Cleanup(server.Close)
+		percipioClient, err := New(ctx, server.URL, "mock", "token", WithMaxRetries(1))
+		require.Nil(t, err)
+		return percipioClient
+	}
+
+	t.Run("should not resend a POST answered with 503", func(t *testing.T) {
+		var requests atomic.Int32
+		percipioClient := newThrottledClient(t, http.StatusServiceUnavailable, &requests)
+
+		_, _, err := percipioClient.CreateUser(ctx, UserRequest{Email: "new.user@example.com"})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+		require.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("should resend a POST answered with 429", func(t *testing.T) {
+		var requests atomic.Int32
+		percipioClient := newThrottledClient(t, http.StatusTooManyRequests, &requests)
+
+		user, _, err := percipioClient.CreateUser(ctx, UserRequest{Email: "new.user@example.com"})
+		require.Nil(t, err)
+		require.Equal(t, "00000000-0000-0000-0000-000000000001", user.Id)
+		require.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("should resend a GET answered with 503", func(t *testing.T) {
+		var requests atomic.Int32
+		percipioClient := newThrottledClient(t, http.StatusServiceUnavailable, &requests)
+
+		_, _, err := percipioClient.GetUser(ctx, "00000000-0000-0000-0000-000000000001")
+		require.Nil(t, err)
+		require.Equal(t, int32(2), requests.Load())
+	})
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	t.Run("should not reserve a token for a cancelled context", func(t *testing.T) {
+		limiter := newRateLimiter(1, 1)
+		cancelled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.ErrorIs(t, limiter.Wait(cancelled), context.Canceled)
+		require.InDelta(t, 1, limiter.tokens, 0.01)
+	})
+}
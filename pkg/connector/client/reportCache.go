@@ -1,28 +1,104 @@
 package client
 
-// StatusesStore is a type alias for an in-memory cache of grant-related data.
-// It is used by the client to store the results of the learning activity report.
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+)
+
+const (
+	StatusCompleted  = "completed"
+	StatusInProgress = "in_progress"
+	StatusPassed     = "passed"
+	StatusFailed     = "failed"
+	StatusUnknown    = "unknown"
+)
+
+// StatusesStore interface abstracts the cache of grant-related data built from the learning activity report.
+// It is used by the client to store the results of the learning activity report and by the course builder to look them up.
+// It holds operations to add and flush report rows, fetch the user statuses for one course, clear the cache, and release any resources.
+// This structure organizes grant lookups so the cache can live in memory or on disk depending on tenant size.
+// Instances are created by `NewMemoryStatusesStore` or `NewSqliteStatusesStore` and attached to the client.
+type StatusesStore interface {
+	Load(ctx context.Context, report *Report) error
+	Add(ctx context.Context, row *ReportEntry) error
+	Flush(ctx context.Context) error
+	Get(ctx context.Context, courseId string) (map[string]string, error)
+	Reset(ctx context.Context) error
+	Close() error
+}
+
+// MemoryStatusesStore is a type alias for an in-memory cache of grant-related data.
+// It is used by the client as the default `StatusesStore`.
 // It holds a nested map where the outer key is a course ID and the inner map links user IDs to their completion status.
 // This structure organizes the report data for efficient lookups when building grants for a specific course.
-// Instances are created by the `New` client function and populated by the `Load` method.
-type StatusesStore map[string]map[string]string
+// Instances are created by the `NewMemoryStatusesStore` function and populated by the `Load` and `Add` methods.
+type MemoryStatusesStore map[string]map[string]string
+
+// NewMemoryStatusesStore function creates an empty in-memory statuses store.
+// It implements the constructor for the default `StatusesStore`.
+// The function allocates the outer map of course IDs.
+// Which provides a ready-to-use cache for tenants small enough to hold their report in RAM.
+// This implementation returns the store as the `StatusesStore` interface.
+func NewMemoryStatusesStore() StatusesStore {
+	return make(MemoryStatusesStore)
+}
 
 // Load method processes a learning activity report and populates the StatusesStore cache.
 // It implements the data hydration for the in-memory grant cache.
 // The method iterates through each row of the report, creating a nested map of course IDs to user IDs to their normalized statuses.
 // Which transforms the flat report data into a structured cache for fast, resource-specific grant lookups.
-// This implementation processes the entire report at once to build the cache in memory.
-func (r StatusesStore) Load(report *Report) error {
-	for _, row := range *report {
-		found, ok := r[row.ContentUUID]
-		if !ok {
-			found = make(map[string]string)
+// This implementation processes an already decoded report by adding each row with `Add`.
+func (r MemoryStatusesStore) Load(ctx context.Context, report *Report) error {
+	for i := range *report {
+		err := r.Add(ctx, &(*report)[i])
+		if err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
 
-		found[row.UserUUID] = toStatus(row.Status)
+// Add method records a single learning activity report row in the StatusesStore cache.
+// It implements the incremental hydration used while a report is streamed from the API.
+// The method stores the row's normalized status under its content ID and user ID.
+// Which lets the report be loaded one row at a time without holding the decoded report in memory.
+// This implementation keeps the higher-precedence status when the same user and content appear more than once,
+// so a "Completed" row is never downgraded by a "Started" row from the same or a later report.
+func (r MemoryStatusesStore) Add(_ context.Context, row *ReportEntry) error {
+	found, ok := r[row.ContentUUID]
+	if !ok {
+		found = make(map[string]string)
 		r[row.ContentUUID] = found
 	}
 
+	status := rowStatus(row)
+	if current, ok := found[row.UserUUID]; ok && statusPrecedence(current) > statusPrecedence(status) {
+		return nil
+	}
+	found[row.UserUUID] = status
+	return nil
+}
+
+// Flush method makes rows added so far visible to lookups.
+// It implements the `Flush` method required by the `StatusesStore` interface.
+// The method has nothing to do because rows are visible as soon as they are added.
+// Which keeps the in-memory and on-disk stores interchangeable.
+// This implementation always returns nil.
+func (r MemoryStatusesStore) Flush(_ context.Context) error {
+	return nil
+}
+
+// Reset method removes every entry from the in-memory cache.
+// It implements the `Reset` method required by the `StatusesStore` interface.
+// The method deletes all course IDs from the outer map.
+// Which lets a full rebuild start from an empty cache.
+// This implementation keeps the same map so existing references stay valid.
+func (r MemoryStatusesStore) Reset(_ context.Context) error {
+	clear(r)
 	return nil
 }
 
@@ -31,12 +107,76 @@ func (r StatusesStore) Load(report *Report) error {
 // The method takes a course ID and returns the corresponding map of user IDs to their statuses.
 // Which provides the grant builder with the necessary data to create grants for a specific course resource.
 // This implementation returns `nil` if the course ID is not found in the cache.
-func (r StatusesStore) Get(courseId string) map[string]string {
+func (r MemoryStatusesStore) Get(_ context.Context, courseId string) (map[string]string, error) {
 	found, ok := r[courseId]
 	if !ok {
-		return nil
+		return nil, nil
+	}
+	return found, nil
+}
+
+// Close method releases the in-memory cache.
+// It implements the `Close` method required by the `StatusesStore` interface.
+// The method has nothing to release because the map is garbage collected.
+// Which keeps the in-memory and on-disk stores interchangeable.
+// This implementation always returns nil.
+func (r MemoryStatusesStore) Close() error {
+	return nil
+}
+
+// statusPrecedence function ranks normalized statuses for merging.
+// It implements the precedence rule applied when the same user and content are reported more than once.
+// The function orders "completed" above "in_progress" above any other status, and for assessments "passed" above "failed" above "in_progress".
+// Which ensures merged reports converge on the furthest progress a user has made.
+// This implementation is shared by the in-memory and on-disk statuses stores; course and assessment statuses never share a content ID.
+func statusPrecedence(status string) int {
+	switch status {
+	case StatusPassed:
+		return 3
+	case StatusCompleted, StatusFailed:
+		return 2
+	case StatusInProgress:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// rowStatus function normalizes the status of a learning activity report row.
+// It implements the dispatch between course and assessment status mapping.
+// The function uses `toAssessmentStatus` for assessment rows and `toStatus` for every other content type.
+// Which lets the assessment builder grant outcomes while courses keep their completion statuses.
+// This implementation relies on the report's `contentType` column to recognize assessments.
+func rowStatus(row *ReportEntry) string {
+	if strings.EqualFold(row.ContentType, config.ContentTypeAssessment) {
+		return toAssessmentStatus(row)
+	}
+	return toStatus(row.Status)
+}
+
+// toAssessmentStatus function normalizes an assessment report row into an assessment outcome.
+// It implements the status mapping required for assessment grants.
+// The function maps explicit "Passed"/"Failed" statuses directly and, for completed attempts, compares the high score with the passing score.
+// Which tells "took the assessment" apart from "passed the assessment".
+// This implementation treats a completed attempt without comparable scores as passed, and defaults to "unknown" for unmapped statuses.
+func toAssessmentStatus(row *ReportEntry) string {
+	switch row.Status {
+	case "Passed":
+		return StatusPassed
+	case "Failed":
+		return StatusFailed
+	case "Started":
+		return StatusInProgress
+	case "Completed":
+		highScore, highErr := strconv.ParseFloat(row.HighScore, 64)
+		passingScore, passingErr := strconv.ParseFloat(row.PassingScore, 64)
+		if highErr == nil && passingErr == nil && highScore < passingScore {
+			return StatusFailed
+		}
+		return StatusPassed
+	default:
+		return StatusUnknown
 	}
-	return found
 }
 
 // toStatus function normalizes a Percipio status string into a connector-compatible status.
@@ -47,10 +187,10 @@ func (r StatusesStore) Get(courseId string) map[string]string {
 func toStatus(status string) string {
 	switch status {
 	case "Started":
-		return "in_progress"
+		return StatusInProgress
 	case "Completed":
-		return "completed"
+		return StatusCompleted
 	default:
-		return "unknown"
+		return StatusUnknown
 	}
 }
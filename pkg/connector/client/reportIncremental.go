@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// WithIncrementalSync function enables incremental learning activity syncs.
+// It implements the option used by the connector to request only the activity since the last successful report.
+// The function turns on incremental mode and sets how often a full rebuild of the grant store is forced.
+// Which avoids re-pulling a decade of activity on every sync for large tenants.
+// This implementation requires a report job store for the watermark and a persistent statuses store for the baseline;
+// without a report job store every sync falls back to a full report.
+func WithIncrementalSync(fullSyncInterval time.Duration) Option {
+	return func(c *Client) {
+		c.incrementalSync = true
+		c.fullSyncInterval = fullSyncInterval
+	}
+}
+
+// reportWindowStart method chooses the start of the next learning activity report window.
+// It implements the decision between an incremental delta report and a full rebuild.
+// The method loads the watermark and returns its end timestamp when incremental mode is on and the last full rebuild is recent enough.
+// Which lets subsequent runs request only the time window that has not been loaded yet.
+// This implementation falls back to the full report window whenever the watermark is missing, unreadable or too old.
+func (c *Client) reportWindowStart(ctx context.Context, now time.Time) (time.Time, bool) {
+	fullStart := c.reportFullStart(now)
+	if !c.incrementalSync || c.reportJobs == nil {
+		return fullStart, false
+	}
+
+	l := ctxzap.Extract(ctx)
+	watermark, err := c.reportJobs.LoadWatermark()
+	if err != nil {
+		l.Warn("failed to load learning activity watermark, running a full report", zap.Error(err))
+		return fullStart, false
+	}
+	if watermark == nil || watermark.End.IsZero() {
+		l.Info("no learning activity watermark found, running a full report")
+		return fullStart, false
+	}
+	if c.fullSyncInterval > 0 && now.Sub(watermark.FullSyncAt) >= c.fullSyncInterval {
+		l.Info("full learning activity rebuild is due",
+			zap.Time("last_full_sync", watermark.FullSyncAt),
+			zap.Duration("full_sync_interval", c.fullSyncInterval),
+		)
+		return fullStart, false
+	}
+
+	l.Info("running an incremental learning activity report", zap.Time("since", watermark.End))
+	return watermark.End, true
+}
+
+// prepareReportLoad method readies the statuses store before a report is loaded into it.
+// It implements the baseline reset that precedes a full rebuild in incremental mode.
+// The method clears the watermark and then empties the statuses store when the current report is a full report.
+// Which ensures a failed rebuild can never be mistaken for a valid baseline by the next incremental run.
+// This implementation does nothing for incremental reports or when incremental mode is off.
+func (c *Client) prepareReportLoad(ctx context.Context) error {
+	if !c.incrementalSync || c.reportJob.Incremental {
+		return nil
+	}
+
+	if c.reportJobs != nil {
+		err := c.reportJobs.ClearWatermark()
+		if err != nil {
+			return err
+		}
+	}
+	return c.StatusesStore.Reset(ctx)
+}
+
+// commitReportWindow method records a successfully loaded report window as the new watermark.
+// It implements the final step of an incremental or full report load.
+// The method flushes the statuses store and then saves the report's end timestamp, carrying over the last full rebuild time for delta reports.
+// Which lets the next sync continue from exactly where this one stopped.
+// This implementation is a no-op when incremental mode is off.
+func (c *Client) commitReportWindow(ctx context.Context) {
+	if !c.incrementalSync || c.reportJobs == nil {
+		return
+	}
+
+	l := ctxzap.Extract(ctx)
+	err := c.StatusesStore.Flush(ctx)
+	if err != nil {
+		l.Warn("failed to flush statuses store, watermark not advanced", zap.Error(err))
+		return
+	}
+
+	watermark := ReportWatermark{
+		End:        c.reportJob.End,
+		FullSyncAt: c.reportJob.End,
+	}
+	if c.reportJob.Incremental {
+		previous, err := c.reportJobs.LoadWatermark()
+		if err != nil || previous == nil {
+			l.Warn("failed to load learning activity watermark, watermark not advanced", zap.Error(err))
+			return
+		}
+		watermark.FullSyncAt = previous.FullSyncAt
+	}
+
+	err = c.reportJobs.SaveWatermark(watermark)
+	if err != nil {
+		l.Warn("failed to save learning activity watermark", zap.Error(err))
+	}
+}
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	ReportJobMaxAge         = 24 * time.Hour
+	reportJobFileName       = "learning-activity-report.json"
+	reportWatermarkFileName = "learning-activity-watermark.json"
+	reportJobPermissions    = 0o600
+)
+
+// ErrReportExpired is returned when a learning activity report can no longer be retrieved.
+// It is used by `GetLearningActivityReport` to signal that a report ID is unknown or expired, or that a resumed report has failed.
+// It holds no additional data; the underlying cause is wrapped alongside it.
+// This variable lets the course grant builder detect the condition and request a new report.
+// The instance is compared with `errors.Is`.
+var ErrReportExpired = errors.New("learning activity report expired or unknown")
+
+// ReportJob struct records an in-flight learning activity report request.
+// It is used by the `ReportJobStore` to persist the report across process restarts and resumed syncs.
+// It holds the report `Id`, its last known `Status`, the requested `Start`/`End` window, when it was requested,
+// and, for sliced reports, the per-slice report `Slices`.
+// This structure organizes everything needed to resume polling an existing report instead of generating a new one.
+// Instances are created by `GenerateLearningActivityReport` and read back by `ResumeLearningActivityReport`.
+type ReportJob struct {
+	Id          string        `json:"id"`
+	Status      string        `json:"status"`
+	Start       time.Time     `json:"start"`
+	End         time.Time     `json:"end"`
+	RequestedAt time.Time     `json:"requestedAt"`
+	Incremental bool          `json:"incremental,omitempty"`
+	Slices      []ReportSlice `json:"slices,omitempty"`
+}
+
+// ReportWatermark struct records the outcome of the last successfully loaded learning activity report.
+// It is used by incremental syncs to decide which time window the next report should cover.
+// It holds the `End` of the last loaded report window and when the last full rebuild completed.
+// This structure organizes the incremental sync cursor separately from any in-flight report job.
+// Instances are written by `GetLearningActivityReport` and read by `GenerateLearningActivityReport`.
+type ReportWatermark struct {
+	End        time.Time `json:"end"`
+	FullSyncAt time.Time `json:"fullSyncAt"`
+}
+
+// ReportJobStore struct persists the current learning activity report job on disk.
+// It is used by the client to survive checkpoints and restarts while a multi-hour report is generating.
+// It holds the paths of small JSON state files scoped to a single Percipio organization.
+// This structure organizes durable report state without requiring any external storage.
+// Instances are created by the `NewReportJobStore` function.
+type ReportJobStore struct {
+	path          string
+	watermarkPath string
+}
+
+// NewReportJobStore function creates a report job store for an organization.
+// It implements the constructor for the on-disk report state.
+// The function places the state files in a subdirectory of `directory` named after the organization ID.
+// Which keeps state from different tenants synced on the same host apart.
+// This implementation does not touch the filesystem until the first save.
+func NewReportJobStore(directory string, organizationId string) *ReportJobStore {
+	return &ReportJobStore{
+		path:          filepath.Join(directory, organizationId, reportJobFileName),
+		watermarkPath: filepath.Join(directory, organizationId, reportWatermarkFileName),
+	}
+}
+
+// Load method reads the persisted report job.
+// It implements the lookup of an in-flight report for a resumed sync.
+// The method decodes the job state file and returns nil when no job has been saved.
+// Which allows callers to distinguish "no report yet" from a read failure.
+// This implementation treats a corrupt state file as an error so it can be cleared by the caller.
+func (s *ReportJobStore) Load() (*ReportJob, error) {
+	var job ReportJob
+	found, err := readStateFile(s.path, &job)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Save method writes the report job to disk.
+// It implements the persistence of a newly requested or updated report.
+// The method writes the job atomically to the state file.
+// Which lets a resumed sync find the report even if the process crashes right after requesting it.
+// This implementation creates the organization directory on demand.
+func (s *ReportJobStore) Save(job ReportJob) error {
+	return writeStateFile(s.path, job)
+}
+
+// Clear method removes the persisted report job.
+// It implements the cleanup once a report has been consumed or found to be unusable.
+// The method deletes the state file.
+// Which ensures the next sync requests a fresh report.
+// This implementation ignores a state file that does not exist.
+func (s *ReportJobStore) Clear() error {
+	err := os.Remove(s.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// LoadWatermark method reads the incremental sync watermark.
+// It implements the lookup of the last successfully loaded report window.
+// The method decodes the watermark file and returns nil when no report has completed yet.
+// Which tells the caller whether an incremental report is possible.
+// This implementation treats a corrupt watermark file as an error.
+func (s *ReportJobStore) LoadWatermark() (*ReportWatermark, error) {
+	var watermark ReportWatermark
+	found, err := readStateFile(s.watermarkPath, &watermark)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &watermark, nil
+}
+
+// SaveWatermark method writes the incremental sync watermark to disk.
+// It implements the persistence of the last successfully loaded report window.
+// The method writes the watermark atomically next to the report job state.
+// Which lets the next sync request only the activity that happened since.
+// This implementation shares the atomic write helper with `Save`.
+func (s *ReportJobStore) SaveWatermark(watermark ReportWatermark) error {
+	return writeStateFile(s.watermarkPath, watermark)
+}
+
+// ClearWatermark method removes the incremental sync watermark.
+// It implements the invalidation performed before a full rebuild replaces the baseline.
+// The method deletes the watermark file.
+// Which forces the next sync to run a full report if the rebuild does not complete.
+// This implementation ignores a watermark file that does not exist.
+func (s *ReportJobStore) ClearWatermark() error {
+	err := os.Remove(s.watermarkPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// readStateFile function decodes a JSON state file into target.
+// It implements the shared read path for report job and watermark state.
+// The function reads and unmarshals the file, reporting whether it existed.
+// Which distinguishes "no state yet" from a read or decode failure.
+// This implementation returns false without error when the file does not exist.
+func readStateFile(path string, target any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	err = json.Unmarshal(data, target)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode report state %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// writeStateFile function atomically writes a value as a JSON state file.
+// It implements the shared write path for report job and watermark state.
+// The function writes the value to a temporary file and renames it over the target path.
+// Which guarantees that a crash mid-write never leaves a truncated state file behind.
+// This implementation creates the organization directory on demand.
+func writeStateFile(path string, value any) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	err = os.WriteFile(tmpPath, data, reportJobPermissions)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// WithReportJobStore function enables durable tracking of learning activity report jobs.
+// It implements the option used by the connector to persist report state under a directory.
+// The function attaches a `ReportJobStore` scoped to the client's organization.
+// Which lets a resumed or restarted sync reuse an in-flight report instead of starting a new one.
+// This implementation leaves tracking disabled when the directory is empty.
+func WithReportJobStore(directory string) Option {
+	return func(c *Client) {
+		if directory == "" {
+			return
+		}
+		c.reportJobs = NewReportJobStore(directory, c.organizationId)
+	}
+}
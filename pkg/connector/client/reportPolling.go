@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+)
+
+// ReportPolling struct holds the timing settings used while waiting for a learning activity report.
+// It is used by `pollLearningActivityReport` to decide how long to wait between status checks and when to give up.
+// It holds the `InitialInterval` for the first wait, the `MaxInterval` that caps the exponential backoff, and the overall `Timeout`.
+// This structure organizes the polling configuration so it can be tuned per tenant instead of relying on constants.
+// Instances are created by the `New` client function with defaults and overridden by the `WithReportPolling` option.
+type ReportPolling struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Timeout         time.Duration
+}
+
+// defaultReportPolling function returns the polling settings used when none are configured.
+// It implements the fallback values for the `ReportPolling` settings.
+// The function converts the defaults declared in the config package into durations.
+// Which keeps the client usable without any polling configuration, for example in tests.
+// This implementation mirrors the default values of the corresponding configuration fields.
+func defaultReportPolling() ReportPolling {
+	return ReportPolling{
+		InitialInterval: config.ReportPollInitialIntervalSecondsDefault * time.Second,
+		MaxInterval:     config.ReportPollMaxIntervalSecondsDefault * time.Second,
+		Timeout:         config.ReportPollTimeoutMinutesDefault * time.Minute,
+	}
+}
+
+// WithReportPolling function configures how the client waits for learning activity reports.
+// It implements the option used by the connector to apply the report polling configuration fields.
+// The function overrides each non-zero duration on the client's `ReportPolling` settings.
+// Which lets operators bound how long a sync may wait on a slow report and how often it checks.
+// This implementation raises the maximum interval to the initial interval when it is configured lower.
+func WithReportPolling(initialInterval time.Duration, maxInterval time.Duration, timeout time.Duration) Option {
+	return func(c *Client) {
+		if initialInterval > 0 {
+			c.reportPolling.InitialInterval = initialInterval
+		}
+		if maxInterval > 0 {
+			c.reportPolling.MaxInterval = maxInterval
+		}
+		if timeout > 0 {
+			c.reportPolling.Timeout = timeout
+		}
+		if c.reportPolling.MaxInterval < c.reportPolling.InitialInterval {
+			c.reportPolling.MaxInterval = c.reportPolling.InitialInterval
+		}
+	}
+}
+
+// nextInterval method computes the backoff interval that follows the given one.
+// It implements the exponential growth step of the report polling backoff.
+// The method doubles the interval and caps it at `MaxInterval`.
+// Which reduces load on the Percipio reporting API while a long report is generating.
+// This implementation never returns less than `InitialInterval`.
+func (p ReportPolling) nextInterval(interval time.Duration) time.Duration {
+	next := min(interval*2, p.MaxInterval)
+	return max(next, p.InitialInterval)
+}
+
+// jitter function randomizes a backoff interval.
+// It implements the jitter applied to each wait in the report polling loop.
+// The function returns a random duration between half of the interval and the full interval.
+// Which prevents many connectors started at the same time from polling in lockstep.
+// This implementation returns the interval unchanged when it is too small to split.
+func jitter(interval time.Duration) time.Duration {
+	half := interval / 2
+	if half <= 0 {
+		return interval
+	}
+	return half + rand.N(interval-half+1)
+}
+
+// sleepContext function waits for the given duration or until the context is done.
+// It implements a cancellable replacement for `time.Sleep` in the report polling loop.
+// The function blocks on a timer and the context's done channel, whichever fires first.
+// Which allows a sync to be cancelled or time out while it is waiting on a report.
+// This implementation returns the context error when the wait was interrupted.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
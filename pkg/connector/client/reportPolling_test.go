@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollLearningActivityReport(t *testing.T) {
+	ctx := context.Background()
+
+	newPollingClient := func(t *testing.T, handler http.HandlerFunc) *Client {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		percipioClient, err := New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			WithReportPolling(10*time.Millisecond, 10*time.Millisecond, 100*time.Millisecond),
+		)
+		require.Nil(t, err)
+		return percipioClient
+	}
+
+	countRows := func(rows *int) func(io.Reader) error {
+		return func(body io.Reader) error {
+			count, err := DecodeReport(body, func(row *ReportEntry) error {
+				return nil
+			})
+			*rows = count
+			return err
+		}
+	}
+
+	t.Run("should stream a finished report past the polling timeout", func(t *testing.T) {
+		percipioClient := newPollingClient(t, func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(`[{"userUuid":"user-1"},`))
+			writer.(http.Flusher).Flush()
+			time.Sleep(300 * time.Millisecond)
+			_, _ = writer.Write([]byte(`{"userUuid":"user-2"}]`))
+		})
+
+		rows := 0
+		_, err := percipioClient.pollLearningActivityReport(ctx, "report-1", countRows(&rows))
+		require.Nil(t, err)
+		require.Equal(t, 2, rows)
+	})
+
+	t.Run("should time out while the report is in progress", func(t *testing.T) {
+		percipioClient := newPollingClient(t, func(writer http.ResponseWriter, request *http.Request) {
+			_, _ = writer.Write([]byte(`{"status":"IN_PROGRESS"}`))
+		})
+
+		rows := 0
+		_, err := percipioClient.pollLearningActivityReport(ctx, "report-1", countRows(&rows))
+		require.ErrorContains(t, err, "report polling timed out")
+		require.Equal(t, 0, rows)
+	})
+
+	t.Run("should return rate limit data when a poll fails", func(t *testing.T) {
+		var requests atomic.Int32
+		percipioClient := newPollingClient(t, func(writer http.ResponseWriter, request *http.Request) {
+			if requests.Add(1) > 1 {
+				connection, _, err := writer.(http.Hijacker).Hijack()
+				require.Nil(t, err)
+				_ = connection.Close()
+				return
+			}
+			writer.Header().Set("X-Ratelimit-Limit", "100")
+			writer.Header().Set("X-Ratelimit-Remaining", "42")
+			_, _ = writer.Write([]byte(`{"status":"IN_PROGRESS"}`))
+		})
+
+		rows := 0
+		ratelimitData, err := percipioClient.pollLearningActivityReport(ctx, "report-1", countRows(&rows))
+		require.NotNil(t, err)
+		require.NotNil(t, ratelimitData)
+		require.Equal(t, int64(42), ratelimitData.Remaining)
+	})
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLearningActivityReportResumed(t *testing.T) {
+	ctx := context.Background()
+
+	newResumedClient := func(t *testing.T, handler http.HandlerFunc) (*Client, *ReportJobStore) {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+		percipioClient, err := New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			WithReportPolling(10*time.Millisecond, 10*time.Millisecond, 100*time.Millisecond),
+			WithReportJobStore(t.TempDir()),
+		)
+		require.Nil(t, err)
+
+		err = percipioClient.reportJobs.Save(ReportJob{
+			Id:          "report-1",
+			Status:      "IN_PROGRESS",
+			RequestedAt: time.Now(),
+		})
+		require.Nil(t, err)
+		require.True(t, percipioClient.ResumeLearningActivityReport(ctx))
+		return percipioClient, percipioClient.reportJobs
+	}
+
+	for _, testCase := range []struct {
+		name        string
+		handler     http.HandlerFunc
+		expired     bool
+		jobRetained bool
+	}{
+		{
+			name: "should expire an unknown report",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(http.StatusNotFound)
+			},
+			expired: true,
+		},
+		{
+			name: "should expire a report that is gone",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(http.StatusGone)
+			},
+			expired: true,
+		},
+		{
+			name: "should expire a failed report",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				_, _ = writer.Write([]byte(`{"status":"FAILED","error":"report generation failed"}`))
+			},
+			expired: true,
+		},
+		{
+			name: "should keep the job when polling is forbidden",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(http.StatusForbidden)
+			},
+			jobRetained: true,
+		},
+		{
+			name: "should keep the job when polling times out",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				_, _ = writer.Write([]byte(`{"status":"IN_PROGRESS"}`))
+			},
+			jobRetained: true,
+		},
+		{
+			name: "should keep the job when the report is truncated",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				_, _ = writer.Write([]byte(`[{"userUuid":"user-1"},{"userUuid":"us`))
+			},
+			jobRetained: true,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			percipioClient, store := newResumedClient(t, testCase.handler)
+
+			_, err := percipioClient.GetLearningActivityReport(ctx)
+			require.NotNil(t, err)
+			require.Equal(t, testCase.expired, errors.Is(err, ErrReportExpired))
+
+			job, err := store.Load()
+			require.Nil(t, err)
+			if testCase.jobRetained {
+				require.NotNil(t, job)
+				require.Equal(t, "report-1", job.Id)
+			} else {
+				require.Nil(t, job)
+			}
+		})
+	}
+}
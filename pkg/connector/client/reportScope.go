@@ -0,0 +1,94 @@
+package client
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+)
+
+// WithReportLookback function configures how far back the full learning activity report reaches.
+// It implements the option used by the connector to apply the lookback and start date configuration fields.
+// The function overrides the default ten year lookback, or pins the report start to an explicit date.
+// Which lets operators trade history for report generation time on very large tenants.
+// This implementation gives a non-zero `startDate` precedence over `lookback`, and ignores a non-positive lookback.
+func WithReportLookback(lookback time.Duration, startDate time.Time) Option {
+	return func(c *Client) {
+		if lookback > 0 {
+			c.reportLookback = lookback
+		}
+		c.reportStartDate = startDate
+	}
+}
+
+// WithContentTypes function configures which Percipio content types are synced and reported on.
+// It implements the option used by the connector to apply the `content-types` configuration field.
+// The function replaces the default Course and Assessment scope with the given report content types.
+// Which keeps the catalog resources, content search and learning activity report on the same set of content.
+// This implementation keeps the default scope when the list is empty and drops duplicate entries.
+func WithContentTypes(contentTypes []string) Option {
+	return func(c *Client) {
+		if len(contentTypes) == 0 {
+			return
+		}
+		scope := make([]string, 0, len(contentTypes))
+		for _, contentType := range contentTypes {
+			if !slices.Contains(scope, contentType) {
+				scope = append(scope, contentType)
+			}
+		}
+		c.contentTypes = scope
+	}
+}
+
+// WithReportAudiences function limits the learning activity report to a set of audiences.
+// It implements the option used by the connector to apply the `report-audiences` configuration field.
+// The function records the audience IDs sent as the report's `audience` filter.
+// Which lets very large tenants generate grants only for the populations they review.
+// This implementation drops empty and duplicate entries, and leaves the report unscoped when none remain.
+func WithReportAudiences(audiences []string) Option {
+	return func(c *Client) {
+		scope := make([]string, 0, len(audiences))
+		for _, audience := range audiences {
+			if audience != "" && !slices.Contains(scope, audience) {
+				scope = append(scope, audience)
+			}
+		}
+		c.reportAudiences = scope
+	}
+}
+
+// PercipioContentTypes method returns the configured content types as catalog `percipioType` values.
+// It implements the lookup used by the course builder and content search to filter catalog items.
+// The method upper-cases each configured report content type, e.g. `Course` becomes `COURSE`.
+// Which lets catalog resources follow the same scope as the learning activity report.
+// This implementation returns a new slice on every call.
+func (c *Client) PercipioContentTypes() []string {
+	percipioTypes := make([]string, 0, len(c.contentTypes))
+	for _, contentType := range c.contentTypes {
+		percipioTypes = append(percipioTypes, strings.ToUpper(contentType))
+	}
+	return percipioTypes
+}
+
+// reportFullStart method returns the start of a full learning activity report window.
+// It implements the lower bound used whenever no incremental watermark applies.
+// The method returns the configured start date, or `now` minus the configured lookback.
+// Which lets a full rebuild skip history the operator does not care about.
+// This implementation prefers the explicit start date when both are configured.
+func (c *Client) reportFullStart(now time.Time) time.Time {
+	if !c.reportStartDate.IsZero() {
+		return c.reportStartDate
+	}
+	return now.Add(-c.reportLookback)
+}
+
+// defaultContentTypes function returns the content types synced when none are configured.
+// It implements the fallback value for the client's content type scope.
+// The function copies the default list declared in the config package.
+// Which keeps the client usable without any scope configuration, for example in tests.
+// This implementation mirrors the default value of the `content-types` configuration field.
+func defaultContentTypes() []string {
+	return slices.Clone(config.ContentTypesDefault)
+}
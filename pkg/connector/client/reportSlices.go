@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ReportSlice struct records one time slice of a learning activity report request.
+// It is used by the client to track the individual reports that together cover a sliced report window.
+// It holds the slice's report `Id`, its last known `Status` and the `Start`/`End` of the slice window.
+// This structure organizes per-slice state so every slice can be polled and resumed independently.
+// Instances are created by `splitReportWindow` and persisted as part of a `ReportJob`.
+type ReportSlice struct {
+	Id     string    `json:"id"`
+	Status string    `json:"status"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// ReportSlicing struct holds the settings used to split a learning activity report into time slices.
+// It is used by `GenerateLearningActivityReport` and `GetLearningActivityReport` to parallelize report generation.
+// It holds the number of slices a full report window is split into and how many slices are requested and polled at once.
+// This structure organizes the slicing configuration so it can be tuned per tenant.
+// Instances are created by the `New` client function with defaults and overridden by the `WithReportSlicing` option.
+type ReportSlicing struct {
+	Count       int
+	Concurrency int
+}
+
+// defaultReportSlicing function returns the slicing settings used when none are configured.
+// It implements the fallback values for the `ReportSlicing` settings.
+// The function copies the defaults declared in the config package.
+// Which keeps a single, unsliced report as the default behavior.
+// This implementation mirrors the default values of the corresponding configuration fields.
+func defaultReportSlicing() ReportSlicing {
+	return ReportSlicing{
+		Count:       config.ReportSlicesDefault,
+		Concurrency: config.ReportConcurrencyDefault,
+	}
+}
+
+// WithReportSlicing function configures time-sliced generation of full learning activity reports.
+// It implements the option used by the connector to apply the report slicing configuration fields.
+// The function sets how many slices a full report window is split into and how many are in flight at once.
+// Which lets Percipio generate several smaller reports in parallel instead of one report covering a decade.
+// This implementation ignores non-positive values and keeps the corresponding default.
+func WithReportSlicing(count int, concurrency int) Option {
+	return func(c *Client) {
+		if count > 0 {
+			c.reportSlicing.Count = count
+		}
+		if concurrency > 0 {
+			c.reportSlicing.Concurrency = concurrency
+		}
+	}
+}
+
+// splitReportWindow function splits a report window into consecutive time slices.
+// It implements the partitioning step of sliced report generation.
+// The function divides the window between `start` and `end` into `count` slices of equal length.
+// Which gives each report request a similar amount of activity to process.
+// This implementation ends the last slice exactly at `end` and returns a single slice when the window cannot be split.
+func splitReportWindow(start time.Time, end time.Time, count int) []ReportSlice {
+	window := end.Sub(start)
+	if count <= 1 || window < time.Duration(count) {
+		return []ReportSlice{{Start: start, End: end}}
+	}
+
+	step := window / time.Duration(count)
+	parts := make([]ReportSlice, count)
+	for i := range parts {
+		parts[i].Start = start.Add(time.Duration(i) * step)
+		parts[i].End = start.Add(time.Duration(i+1) * step)
+	}
+	parts[count-1].End = end
+	return parts
+}
+
+// reportSlices method returns the time slices that make up a report job.
+// It implements a uniform view over sliced and unsliced report jobs.
+// The method returns the job's `Slices`, or a single slice built from the job itself when it was not sliced.
+// Which lets polling and resuming handle both kinds of jobs with the same code.
+// This implementation returns the job's own slice of slices, so callers may update statuses in place.
+func (j ReportJob) reportSlices() []ReportSlice {
+	if len(j.Slices) > 0 {
+		return j.Slices
+	}
+	return []ReportSlice{{Id: j.Id, Status: j.Status, Start: j.Start, End: j.End}}
+}
+
+// requestReportSlices method submits one learning activity report request per time slice.
+// It implements the fan-out step of sliced report generation.
+// The method posts a `ReportConfigurations` body for each slice, at most `Concurrency` at a time, and records the returned report IDs.
+// Which lets Percipio work on every slice of the window at the same time.
+// This implementation stops submitting new slices after the first failure and returns that error.
+func (c *Client) requestReportSlices(ctx context.Context, parts []ReportSlice) (*v2.RateLimitDescription, error) {
+	var mu sync.Mutex
+	var ratelimitData *v2.RateLimitDescription
+	contentTypes := strings.Join(c.contentTypes, ",")
+	audiences := strings.Join(c.reportAudiences, ",")
+
+	err := forEachReportSlice(ctx, len(parts), c.reportSlicing.Concurrency, func(ctx context.Context, i int) error {
+		body := ReportConfigurations{
+			End:         parts[i].End,
+			Start:       parts[i].Start,
+			ContentType: contentTypes,
+			Audience:    audiences,
+		}
+
+		var target ReportStatus
+		response, sliceRatelimitData, err := c.post(ctx, ApiPathLearningActivityReport, body, &target)
+
+		mu.Lock()
+		if sliceRatelimitData != nil {
+			ratelimitData = sliceRatelimitData
+		}
+		mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		parts[i].Id = target.Id
+		parts[i].Status = target.Status
+		if len(parts) > 1 {
+			ctxzap.Extract(ctx).Debug("requested learning activity report slice",
+				zap.String("report_id", target.Id),
+				zap.Time("start", parts[i].Start),
+				zap.Time("end", parts[i].End),
+			)
+		}
+		return nil
+	})
+	return ratelimitData, err
+}
+
+// forEachReportSlice function runs fn once for every slice index with bounded concurrency.
+// It implements the worker pool shared by report submission and report polling.
+// The function starts at most `concurrency` calls at once and cancels the context passed to the remaining calls after the first failure.
+// Which keeps the number of simultaneous Percipio report requests within the configured limit.
+// This implementation waits for every started call to return and reports the first error, or the parent context's error.
+func forEachReportSlice(ctx context.Context, count int, concurrency int, fn func(context.Context, int) error) error {
+	sliceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	semaphore := make(chan struct{}, max(1, min(concurrency, count)))
+	for i := range count {
+		if sliceCtx.Err() != nil {
+			break
+		}
+
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := fn(sliceCtx, i)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// DecodeReport function streams the rows of a learning activity report.
+// It implements the row-by-row decoding required to process multi-gigabyte reports.
+// The function reads the opening bracket of the JSON array, decodes one `ReportEntry` at a time, and passes each row to `fn`.
+// Which keeps only a single row in memory at any point instead of the whole report body.
+// This implementation returns the number of rows processed and stops at the first decoding or callback error,
+// treating a body that ends before the closing bracket as truncated.
+func DecodeReport(reader io.Reader, fn func(row *ReportEntry) error) (int, error) {
+	decoder := json.NewDecoder(reader)
+
+	token, err := decoder.Token()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read learning activity report: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return 0, fmt.Errorf("unexpected learning activity report token: %v", token)
+	}
+
+	rows := 0
+	for decoder.More() {
+		var row ReportEntry
+		err = decoder.Decode(&row)
+		if err != nil {
+			return rows, fmt.Errorf("failed to decode learning activity report row %d: %w", rows, err)
+		}
+
+		err = fn(&row)
+		if err != nil {
+			return rows, err
+		}
+		rows++
+	}
+
+	_, err = decoder.Token()
+	if err != nil {
+		return rows, fmt.Errorf("failed to read end of learning activity report: %w", err)
+	}
+
+	return rows, nil
+}
+
+// peekFirstByte function returns the first non-whitespace byte of a response body without consuming it.
+// It implements the format detection used by report polling to tell a finished report from a status document.
+// The function discards leading whitespace and then peeks at the next byte.
+// Which allows the body to be handed to a streaming decoder untouched.
+// This implementation returns `io.EOF` for an empty or whitespace-only body.
+func peekFirstByte(reader *bufio.Reader) (byte, error) {
+	for {
+		next, err := reader.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(next[0])) {
+			return next[0], nil
+		}
+		_, err = reader.Discard(1)
+		if err != nil {
+			return 0, err
+		}
+	}
+}
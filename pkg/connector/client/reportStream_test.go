@@ -0,0 +1,88 @@
+package client
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeReport(t *testing.T) {
+	for _, testCase := range []struct {
+		name          string
+		body          string
+		expectedRows  []string
+		expectedError string
+	}{
+		{
+			name:         "empty array",
+			body:         `[]`,
+			expectedRows: []string{},
+		},
+		{
+			name:         "single row",
+			body:         ` [{"userUuid":"user-1","contentUuid":"course-1","status":"Completed"}] `,
+			expectedRows: []string{"user-1"},
+		},
+		{
+			name:         "several rows",
+			body:         `[{"userUuid":"user-1"},{"userUuid":"user-2"}]`,
+			expectedRows: []string{"user-1", "user-2"},
+		},
+		{
+			name:          "truncated inside a row",
+			body:          `[{"userUuid":"user-1"},{"userUuid":"us`,
+			expectedRows:  []string{"user-1"},
+			expectedError: "failed to decode learning activity report row 1",
+		},
+		{
+			name:          "truncated between rows",
+			body:          `[{"userUuid":"user-1"}`,
+			expectedRows:  []string{"user-1"},
+			expectedError: "learning activity report",
+		},
+		{
+			name:          "empty body",
+			body:          ``,
+			expectedRows:  []string{},
+			expectedError: "failed to read learning activity report",
+		},
+		{
+			name:          "object body",
+			body:          `{"status":"IN_PROGRESS"}`,
+			expectedRows:  []string{},
+			expectedError: "unexpected learning activity report token",
+		},
+		{
+			name:          "scalar body",
+			body:          `"done"`,
+			expectedRows:  []string{},
+			expectedError: "unexpected learning activity report token",
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			rows := make([]string, 0)
+			count, err := DecodeReport(strings.NewReader(testCase.body), func(row *ReportEntry) error {
+				rows = append(rows, row.UserUUID)
+				return nil
+			})
+			if testCase.expectedError == "" {
+				require.Nil(t, err)
+			} else {
+				require.ErrorContains(t, err, testCase.expectedError)
+			}
+			require.Equal(t, testCase.expectedRows, rows)
+			require.Equal(t, len(testCase.expectedRows), count)
+		})
+	}
+
+	t.Run("should stop at the first callback error", func(t *testing.T) {
+		stop := errors.New("stop")
+		count, err := DecodeReport(strings.NewReader(`[{"userUuid":"user-1"},{"userUuid":"user-2"}]`), func(row *ReportEntry) error {
+			return stop
+		})
+		require.ErrorIs(t, err, stop)
+		require.Equal(t, 0, count)
+	})
+}
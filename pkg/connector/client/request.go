@@ -9,6 +9,8 @@ import (
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 // getUrl method constructs a full URL for an API request.
@@ -74,7 +76,7 @@ func (c *Client) get(
 }
 
 // post method performs a POST request to a specified API path.
-// It implements a generic helper for making POST requests, used by `GenerateLearningActivityReport`.
+// It implements a generic helper for making POST requests, used by `GenerateLearningActivityReport`, `CreateAssignment` and `CreateUser`.
 // The method wraps the more generic `doRequest` function, setting the HTTP method to POST and passing through the path, body, and target struct.
 // Which simplifies the process of making POST requests within the client.
 // This implementation acts as a convenient shorthand for `doRequest` with `http.MethodPost`.
@@ -98,9 +100,58 @@ func (c *Client) post(
 	)
 }
 
+// patch method performs a PATCH request to a specified API path.
+// It implements a generic helper for making PATCH requests, used by `UpdateUser`.
+// The method wraps the more generic `doRequest` function, setting the HTTP method to PATCH and passing through the path, body, and target struct.
+// Which simplifies the process of making PATCH requests within the client.
+// This implementation acts as a convenient shorthand for `doRequest` with `http.MethodPatch`.
+func (c *Client) patch(
+	ctx context.Context,
+	path string,
+	body interface{},
+	target interface{},
+) (
+	*http.Response,
+	*v2.RateLimitDescription,
+	error,
+) {
+	return c.doRequest(
+		ctx,
+		http.MethodPatch,
+		path,
+		nil,
+		body,
+		&target,
+	)
+}
+
+// delete method performs a DELETE request to a specified API path.
+// It implements a generic helper for making DELETE requests, used by `DeleteAssignment` and `DeleteUser`.
+// The method wraps the more generic `doRequest` function, setting the HTTP method to DELETE and passing through the path.
+// Which simplifies the process of making DELETE requests within the client.
+// This implementation acts as a convenient shorthand for `doRequest` with `http.MethodDelete` and discards any response body.
+func (c *Client) delete(
+	ctx context.Context,
+	path string,
+) (
+	*http.Response,
+	*v2.RateLimitDescription,
+	error,
+) {
+	return c.doRequest(
+		ctx,
+		http.MethodDelete,
+		path,
+		nil,
+		nil,
+		nil,
+	)
+}
+
 // doRequest method is the central function for executing all HTTP requests.
-// It implements the core request logic for the Percipio client, used by `get` and `post` helpers.
-// The method constructs the full URL, sets up request options (including authentication and body payload), creates the request, and executes it using the `uhttp.BaseHttpClient`.
+// It implements the core request logic for the Percipio client, used by the `get`, `post`, `patch` and `delete` helpers.
+// The method delegates to `sendRequest` and, when the API answers 401 and the token source can be refreshed, invalidates the cached token and retries exactly once.
+// Requests answered with 429, or with 503 for idempotent methods, are retried up to `maxRetries` times, waiting as long as `Retry-After` or the rate limit reset headers ask.
 // Which ensures that all outgoing API calls are handled consistently, with proper headers, authentication, and error handling.
 // This implementation leverages the `baton-sdk/pkg/uhttp` package to handle low-level request execution, response parsing, and rate limit data extraction.
 func (c *Client) doRequest(
@@ -115,9 +166,76 @@ func (c *Client) doRequest(
 	*v2.RateLimitDescription,
 	error,
 ) {
+	l := ctxzap.Extract(ctx)
+	refreshed := false
+	retries := 0
+	for {
+		response, ratelimitData, err := c.sendRequest(ctx, method, path, queryParameters, payload, target)
+		if err == nil || response == nil {
+			return response, ratelimitData, err
+		}
+
+		if response.StatusCode == http.StatusUnauthorized && !refreshed && c.tokenSource.Invalidate() {
+			l.Debug("percipio api returned 401, retrying with a refreshed token",
+				zap.String("method", method),
+				zap.String("path", path),
+			)
+			refreshed = true
+			continue
+		}
+
+		if isThrottledStatus(method, response.StatusCode) && retries < c.maxRetries {
+			wait := retryDelay(response, retries)
+			retries++
+			l.Warn("percipio api is throttling requests, retrying",
+				zap.String("method", method),
+				zap.String("path", path),
+				zap.Int("status_code", response.StatusCode),
+				zap.Int("retry", retries),
+				zap.Duration("wait", wait),
+			)
+			if sleepContext(ctx, wait) != nil {
+				return response, ratelimitData, err
+			}
+			continue
+		}
+
+		return response, ratelimitData, err
+	}
+}
+
+// sendRequest method performs a single authenticated HTTP request against the Percipio API.
+// It implements one attempt of the request logic wrapped by `doRequest`.
+// The method waits for the client's rate limiter, constructs the full URL, obtains a bearer token from the client's token source,
+// sets up request options, and executes the request using the `uhttp.BaseHttpClient`, or `doUncached` for GET requests made with an `Uncached` context.
+// Which isolates one round trip so that `doRequest` can repeat it after refreshing credentials.
+// This implementation returns the response even on error so callers can inspect the status code,
+// reports non-2xx responses as an `APIError`, and skips decoding the body when there is no target.
+func (c *Client) sendRequest(
+	ctx context.Context,
+	method string,
+	path string,
+	queryParameters map[string]any,
+	payload any,
+	target any,
+) (
+	*http.Response,
+	*v2.RateLimitDescription,
+	error,
+) {
+	err := c.rateLimiter.Wait(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	options := []uhttp.RequestOption{
 		uhttp.WithAcceptJSONHeader(),
-		WithBearerToken(c.bearerToken),
+		WithBearerToken(token),
 	}
 	if payload != nil {
 		options = append(options, uhttp.WithJSONBody(payload))
@@ -131,12 +249,26 @@ func (c *Client) doRequest(
 	}
 
 	var ratelimitData v2.RateLimitDescription
-	response, err := c.wrapper.Do(
-		request,
+	doOptions := []uhttp.DoOption{
 		uhttp.WithRatelimitData(&ratelimitData),
-		uhttp.WithJSONResponse(target),
-	)
+	}
+	if target != nil {
+		doOptions = append(doOptions, uhttp.WithJSONResponse(target))
+	}
+	var response *http.Response
+	if method == http.MethodGet && isUncached(ctx) {
+		response, err = c.doUncached(request, doOptions...)
+	} else {
+		response, err = c.wrapper.Do(request, doOptions...)
+	}
 	if err != nil {
+		if response != nil && response.StatusCode >= http.StatusBadRequest {
+			apiErr := newAPIError(response, method, url.String(), err)
+			if ratelimitData.ResetAt != nil {
+				apiErr.RateLimit = &ratelimitData
+			}
+			return response, &ratelimitData, apiErr
+		}
 		return response, &ratelimitData, fmt.Errorf("error making %s request to %s: %w", method, url, err)
 	}
 
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"strings"
+)
+
+// WithDefaultRole function configures the role users fall back to.
+// It implements the option used by the connector to apply the `default-role` configuration field.
+// The function stores the Percipio role code given to users whose role is revoked and to new users created without a role.
+// Which keeps role revocation from leaving a user without any role.
+// This implementation ignores an empty role and keeps the `LEARNER` default.
+func WithDefaultRole(role string) Option {
+	return func(c *Client) {
+		role = strings.TrimSpace(role)
+		if role == "" {
+			return
+		}
+		c.defaultRole = role
+	}
+}
+
+// DefaultRole method returns the role users fall back to.
+// It implements the lookup used by role provisioning and account creation.
+// The method returns the role configured with `WithDefaultRole`.
+// Which keeps the configuration in one place, like the content type scope.
+// This implementation returns `LEARNER` unless configured otherwise.
+func (c *Client) DefaultRole() string {
+	return c.defaultRole
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	// Registers the pure-Go "sqlite" database/sql driver, the same one used by the baton-sdk.
+	_ "github.com/glebarez/go-sqlite"
+)
+
+const (
+	sqliteStatusesFileName  = "statuses.db"
+	sqliteStatusesBatchSize = 10000
+)
+
+// SqliteStatusesStore struct is a disk-backed cache of grant-related data.
+// It is used by the client instead of the in-memory store for tenants whose report does not fit in RAM.
+// It holds a SQLite database with one row per content/user pair, plus the open write transaction used to batch inserts.
+// This structure organizes the report data on disk, indexed by content ID, so per-course grant lookups stay cheap.
+// Instances are created by the `NewSqliteStatusesStore` function.
+type SqliteStatusesStore struct {
+	db         *sql.DB
+	path       string
+	persistent bool
+	mu         sync.Mutex
+	tx         *sql.Tx
+	insert     *sql.Stmt
+	pending    int
+}
+
+// NewSqliteStatusesStore function creates a disk-backed statuses store for an organization.
+// It implements the constructor for the on-disk `StatusesStore`.
+// The function opens a SQLite file under `directory`, scoped by organization ID, and prepares the statuses table.
+// Which keeps memory usage flat regardless of how many user/course pairs the learning activity report contains.
+// This implementation starts from an empty file unless `persistent` is set, in which case the file is kept between runs
+// so it can serve as the baseline for incremental syncs.
+func NewSqliteStatusesStore(
+	ctx context.Context,
+	directory string,
+	organizationId string,
+	persistent bool,
+) (*SqliteStatusesStore, error) {
+	path := filepath.Join(directory, organizationId, sqliteStatusesFileName)
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	if !persistent {
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	store := &SqliteStatusesStore{
+		path:       path,
+		persistent: persistent,
+	}
+	err = store.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// open method opens the SQLite file and prepares the statuses table, unless the database is already open.
+// It implements the lazy opening shared by the constructor and every method that reads or writes rows.
+// The method creates the file if needed and applies the schema.
+// Which lets a store closed at the end of one sync be used again by the next sync of a long-running connector.
+// This implementation must be called with the store's mutex held, or before the store is shared.
+func (s *SqliteStatusesStore) open(ctx context.Context) error {
+	if s.db != nil {
+		return nil
+	}
+
+	db, err := sql.Open("sqlite", s.path)
+	if err != nil {
+		return err
+	}
+	db.SetMaxOpenConns(1)
+
+	statements := []string{
+		"PRAGMA journal_mode = WAL",
+		"PRAGMA synchronous = NORMAL",
+		`CREATE TABLE IF NOT EXISTS statuses (
+			content_id TEXT NOT NULL,
+			user_id    TEXT NOT NULL,
+			status     TEXT NOT NULL,
+			precedence INTEGER NOT NULL,
+			PRIMARY KEY (content_id, user_id)
+		) WITHOUT ROWID`,
+	}
+	for _, statement := range statements {
+		_, err = db.ExecContext(ctx, statement)
+		if err != nil {
+			_ = db.Close()
+			return fmt.Errorf("failed to initialize statuses store %s: %w", s.path, err)
+		}
+	}
+
+	s.db = db
+	return nil
+}
+
+// Load method processes a learning activity report and populates the on-disk cache.
+// It implements the `Load` method required by the `StatusesStore` interface.
+// The method adds each row of an already decoded report with `Add`.
+// Which keeps the on-disk store usable wherever the in-memory store is.
+// This implementation flushes the pending batch before returning.
+func (s *SqliteStatusesStore) Load(ctx context.Context, report *Report) error {
+	for i := range *report {
+		err := s.Add(ctx, &(*report)[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+// Add method records a single learning activity report row in the on-disk cache.
+// It implements the `Add` method required by the `StatusesStore` interface.
+// The method upserts the row's normalized status inside a batched write transaction, keeping the higher-precedence status on conflict.
+// Which lets a streamed report be written to disk without a transaction per row.
+// This implementation commits the batch every `sqliteStatusesBatchSize` rows.
+func (s *SqliteStatusesStore) Add(ctx context.Context, row *ReportEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.open(ctx)
+	if err != nil {
+		return err
+	}
+
+	if s.tx == nil {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		insert, err := tx.PrepareContext(ctx, `INSERT INTO statuses (content_id, user_id, status, precedence) VALUES (?, ?, ?, ?)
+			ON CONFLICT (content_id, user_id) DO UPDATE SET status = excluded.status, precedence = excluded.precedence
+			WHERE excluded.precedence >= statuses.precedence`)
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		s.tx = tx
+		s.insert = insert
+	}
+
+	status := rowStatus(row)
+	_, err = s.insert.ExecContext(ctx, row.ContentUUID, row.UserUUID, status, statusPrecedence(status))
+	if err != nil {
+		return err
+	}
+
+	s.pending++
+	if s.pending >= sqliteStatusesBatchSize {
+		return s.flush()
+	}
+	return nil
+}
+
+// Flush method commits rows buffered by `Add` to disk.
+// It implements the `Flush` method required by the `StatusesStore` interface.
+// The method commits the pending write transaction under the store's mutex.
+// Which guarantees a fully loaded report is durable before the incremental watermark moves past it.
+// This implementation is a no-op when no rows are pending.
+func (s *SqliteStatusesStore) Flush(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flush()
+}
+
+// flush method commits the pending write transaction, if any.
+// It implements the batching boundary shared by `Add`, `Flush`, `Get` and `Close`.
+// The method closes the prepared insert statement and commits the transaction.
+// Which makes buffered rows visible to subsequent lookups.
+// This implementation must be called with the store's mutex held.
+func (s *SqliteStatusesStore) flush() error {
+	if s.tx == nil {
+		return nil
+	}
+
+	_ = s.insert.Close()
+	err := s.tx.Commit()
+	s.tx = nil
+	s.insert = nil
+	s.pending = 0
+	return err
+}
+
+// Get method retrieves all user-to-status relationships for a given course ID from the on-disk cache.
+// It implements the `Get` method required by the `StatusesStore` interface.
+// The method queries the statuses table by content ID and builds a map of user IDs to statuses.
+// Which provides the grant builder with the data for one course without loading any other course into memory.
+// This implementation returns `nil` if the course ID is not found in the cache.
+func (s *SqliteStatusesStore) Get(ctx context.Context, courseId string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = s.flush()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT user_id, status FROM statuses WHERE content_id = ?", courseId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var found map[string]string
+	for rows.Next() {
+		var userId, status string
+		err = rows.Scan(&userId, &status)
+		if err != nil {
+			return nil, err
+		}
+		if found == nil {
+			found = make(map[string]string)
+		}
+		found[userId] = status
+	}
+	return found, rows.Err()
+}
+
+// Reset method removes every entry from the on-disk cache.
+// It implements the `Reset` method required by the `StatusesStore` interface.
+// The method commits any pending batch and deletes all rows from the statuses table.
+// Which lets a full rebuild replace a persisted incremental baseline.
+// This implementation keeps the database file and schema in place.
+func (s *SqliteStatusesStore) Reset(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.open(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx, "DELETE FROM statuses")
+	return err
+}
+
+// Close method releases the database and deletes its file.
+// It implements the `Close` method required by the `StatusesStore` interface.
+// The method commits any pending batch, closes the database handle and, unless the store is persistent, removes the SQLite file.
+// Which leaves no report data, and so no learner data, on disk once a sync is over.
+// This implementation ignores a file that has already been removed, and reopens an empty file if the store is used again.
+func (s *SqliteStatusesStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.db != nil {
+		err = errors.Join(s.flush(), s.db.Close())
+		s.db = nil
+	}
+	if s.persistent {
+		return err
+	}
+	for _, path := range []string{s.path, s.path + "-wal", s.path + "-shm"} {
+		removeErr := os.Remove(path)
+		if removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+			err = errors.Join(err, removeErr)
+		}
+	}
+	return err
+}
+
+// WithStatusesStore function replaces the client's default in-memory statuses store.
+// It implements the option used by the connector to select the grant store backend.
+// The function sets the `StatusesStore` the client loads the learning activity report into.
+// Which allows very large tenants to keep grant data on disk instead of in memory.
+// This implementation ignores a nil store and keeps the default.
+func WithStatusesStore(store StatusesStore) Option {
+	return func(c *Client) {
+		if store == nil {
+			return
+		}
+		c.StatusesStore = store
+	}
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSqliteStatusesStore(t *testing.T) {
+	ctx := context.Background()
+
+	newStore := func(t *testing.T, directory string, persistent bool) *SqliteStatusesStore {
+		store, err := NewSqliteStatusesStore(ctx, directory, "mock", persistent)
+		require.Nil(t, err)
+		return store
+	}
+
+	t.Run("should add rows and get them by content ID", func(t *testing.T) {
+		store := newStore(t, t.TempDir(), false)
+		defer store.Close()
+
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: "user-1", Status: "Completed"}))
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: "user-2", Status: "Started"}))
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-2", UserUUID: "user-1", Status: "Started"}))
+
+		statuses, err := store.Get(ctx, "course-1")
+		require.Nil(t, err)
+		require.Equal(t, map[string]string{"user-1": StatusCompleted, "user-2": StatusInProgress}, statuses)
+
+		statuses, err = store.Get(ctx, "course-3")
+		require.Nil(t, err)
+		require.Nil(t, statuses)
+	})
+
+	t.Run("should keep the higher precedence status", func(t *testing.T) {
+		store := newStore(t, t.TempDir(), false)
+		defer store.Close()
+
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: "user-1", Status: "Started"}))
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: "user-1", Status: "Completed"}))
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: "user-1", Status: "Started"}))
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "assessment-1", UserUUID: "user-1", ContentType: "Assessment", Status: "Passed"}))
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "assessment-1", UserUUID: "user-1", ContentType: "Assessment", Status: "Failed"}))
+
+		statuses, err := store.Get(ctx, "course-1")
+		require.Nil(t, err)
+		require.Equal(t, StatusCompleted, statuses["user-1"])
+
+		statuses, err = store.Get(ctx, "assessment-1")
+		require.Nil(t, err)
+		require.Equal(t, StatusPassed, statuses["user-1"])
+	})
+
+	t.Run("should flush rows in batches", func(t *testing.T) {
+		directory := t.TempDir()
+		store := newStore(t, directory, true)
+		defer store.Close()
+
+		for i := 0; i < sqliteStatusesBatchSize+1; i++ {
+			require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: fmt.Sprintf("user-%d", i), Status: "Completed"}))
+		}
+		require.Equal(t, 1, store.pending)
+
+		require.Nil(t, store.Flush(ctx))
+		require.Equal(t, 0, store.pending)
+		require.Nil(t, store.Close())
+
+		reopened := newStore(t, directory, true)
+		defer reopened.Close()
+		statuses, err := reopened.Get(ctx, "course-1")
+		require.Nil(t, err)
+		require.Len(t, statuses, sqliteStatusesBatchSize+1)
+	})
+
+	t.Run("should reset every row", func(t *testing.T) {
+		store := newStore(t, t.TempDir(), false)
+		defer store.Close()
+
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: "user-1", Status: "Completed"}))
+		require.Nil(t, store.Reset(ctx))
+
+		statuses, err := store.Get(ctx, "course-1")
+		require.Nil(t, err)
+		require.Nil(t, statuses)
+	})
+
+	t.Run("should remove the file on close unless persistent", func(t *testing.T) {
+		directory := t.TempDir()
+		path := filepath.Join(directory, "mock", sqliteStatusesFileName)
+
+		store := newStore(t, directory, false)
+		require.Nil(t, store.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: "user-1", Status: "Completed"}))
+		require.FileExists(t, path)
+		require.Nil(t, store.Close())
+		require.NoFileExists(t, path)
+		require.NoFileExists(t, path+"-wal")
+
+		statuses, err := store.Get(ctx, "course-1")
+		require.Nil(t, err)
+		require.Nil(t, statuses)
+		require.Nil(t, store.Close())
+		_, err = os.Stat(path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		persistent := newStore(t, directory, true)
+		require.Nil(t, persistent.Add(ctx, &ReportEntry{ContentUUID: "course-1", UserUUID: "user-1", Status: "Completed"}))
+		require.Nil(t, persistent.Close())
+		require.FileExists(t, path)
+	})
+}
@@ -0,0 +1,21 @@
+package client
+
+// WithUserDeletion function configures whether the connector may delete users.
+// It implements the option used by the connector to apply the `allow-user-deletion` configuration field.
+// The function stores whether `DeleteUser` may be called by the user resource deleter.
+// Which keeps an irreversible operation off unless an operator opts in.
+// This implementation leaves deletion disabled by default.
+func WithUserDeletion(enabled bool) Option {
+	return func(c *Client) {
+		c.userDeletion = enabled
+	}
+}
+
+// UserDeletionEnabled method reports whether the connector may delete users.
+// It implements the lookup used by the user resource deleter before it calls the API.
+// The method returns the value configured with `WithUserDeletion`.
+// Which keeps the configuration in one place, like the default role.
+// This implementation returns false unless configured otherwise.
+func (c *Client) UserDeletionEnabled() bool {
+	return c.userDeletion
+}
@@ -11,25 +11,74 @@ import (
 	mapset "github.com/deckarep/golang-set/v2"
 )
 
+// syncCache interface is implemented by the in-memory snapshots the builders share during a sync.
+// It is used by the connector's `Cleanup` to empty every snapshot once a sync is over.
+// It declares a single `reset` method that drops the cached data but keeps the snapshot usable.
+// This interface lets a long-running connector, which builds its syncers only once, read fresh data on every sync.
+// Implementations are the user directory, the content catalog and the group indexes derived from them.
+type syncCache interface {
+	reset()
+}
+
 // Connector struct is the main entry point for the Percipio connector.
 // It is defined by the baton-sdk and is responsible for managing the connector's state.
-// It holds the API client and a set of course IDs to limit the sync scope.
+// It holds the API client, a set of course IDs to limit the sync scope, and the caches shared by the resource syncers.
 // This structure organizes the connector's dependencies and configuration.
 // Instances are created by the New function with configuration provided at startup.
 type Connector struct {
 	client       *client.Client
 	limitCourses mapset.Set[string]
+	caches       []syncCache
 }
 
 // ResourceSyncers method returns a list of resource syncers for the connector.
 // It implements the `ResourceSyncers` method required by the `connectorbuilder.Connector` interface.
-// The method initializes and returns a `ResourceSyncer` for each resource type (users and courses) that the connector should sync.
+// The method initializes and returns a `ResourceSyncer` for each resource type (the organization, users, roles, audiences, channels, journeys, courses and assessments) that the connector should sync.
 // Which provides the baton-sdk with the necessary builders to handle the synchronization of each resource type.
-// This implementation returns a fixed list containing a user builder and a course builder.
+// This implementation returns an organization builder as the root, followed by a user builder, a role builder, an audience builder, a channel builder,
+// a journey builder, a course builder and an assessment builder, plus an attribute group builder when `attribute-groups` is configured,
+// hands the four content builders a single `contentCatalog` so the catalog is walked once per sync,
+// annotates the organization with every other resource type as a child,
+// and records the shared caches so `Cleanup` can empty them after each sync.
 func (d *Connector) ResourceSyncers(ctx context.Context) []connectorbuilder.ResourceSyncer {
-	return []connectorbuilder.ResourceSyncer{
+	users := newUserDirectory(d.client)
+	catalog := newContentCatalog(d.client, d.limitCourses)
+	channels := newChannelBuilder(d.client, catalog)
+	journeys := newJourneyBuilder(d.client, catalog)
+	children := []connectorbuilder.ResourceSyncer{
 		newUserBuilder(d.client),
-		newCourseBuilder(d.client, d.limitCourses),
+		newRoleBuilder(d.client, users),
+		newAudienceBuilder(d.client),
+		channels,
+		journeys,
+		newCourseBuilder(d.client, catalog),
+		newAssessmentBuilder(d.client, catalog),
+	}
+	d.caches = []syncCache{users, catalog, channels.index, journeys.index}
+	if len(d.client.AttributeGroups()) > 0 {
+		attributeGroups := newAttributeGroupBuilder(d.client, users)
+		children = append(children, attributeGroups)
+		d.caches = append(d.caches, attributeGroups)
+	}
+
+	childTypeIds := make([]string, 0, len(children))
+	for _, child := range children {
+		childTypeIds = append(childTypeIds, child.ResourceType(ctx).Id)
+	}
+	return append(
+		[]connectorbuilder.ResourceSyncer{newOrganizationBuilder(d.client, users, childTypeIds)},
+		children...,
+	)
+}
+
+// Cleanup method empties the caches shared by the resource syncers.
+// It implements the connector side of the baton-sdk's end-of-sync cleanup, called by the connector server in main.
+// The method resets every cache recorded by `ResourceSyncers`.
+// Which is required because the baton-sdk calls `ResourceSyncers` only once, so a long-running connector would otherwise serve the first sync's users and catalog forever.
+// This implementation is a no-op before `ResourceSyncers` has been called.
+func (d *Connector) Cleanup(ctx context.Context) {
+	for _, cache := range d.caches {
+		cache.reset()
 	}
 }
 
@@ -44,13 +93,14 @@ func (d *Connector) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.R
 
 // Metadata method returns descriptive metadata about the connector.
 // It implements the `Metadata` method required by the `connectorbuilder.Connector` interface.
-// The method returns a `ConnectorMetadata` object containing the display name and description.
-// Which provides the Baton application with information about the connector's purpose.
+// The method returns a `ConnectorMetadata` object containing the display name, description and account creation schema.
+// Which provides the Baton application with information about the connector's purpose and the fields needed to create a user.
 // This implementation returns a static object with pre-defined text.
 func (d *Connector) Metadata(ctx context.Context) (*v2.ConnectorMetadata, error) {
 	return &v2.ConnectorMetadata{
-		DisplayName: "Percipio Connector",
-		Description: "Connector syncing users from Percipio",
+		DisplayName:           "Percipio Connector",
+		Description:           "Connector syncing users from Percipio",
+		AccountCreationSchema: accountCreationSchema(),
 	}, nil
 }
 
@@ -65,20 +115,30 @@ func (d *Connector) Validate(ctx context.Context) (annotations.Annotations, erro
 
 // New function creates and initializes a new Percipio Connector.
 // It implements the constructor required by the main application to start the connector.
-// The function initializes a new Percipio API client and constructs the `Connector` struct with the client and any course limitations.
+// The function resolves the API base URL from the region or explicit base URL, initializes a new Percipio API client, and constructs the `Connector` struct with the client and any course limitations.
 // Which provides a fully configured instance of the connector, ready to be used by the baton-sdk.
-// This implementation uses `mapset` to efficiently store and check for limited courses if they are provided.
+// This implementation uses `mapset` to efficiently store and check for limited courses if they are provided,
+// and forwards any `client.Option` values (such as service-account credentials) to the API client.
 func New(
 	ctx context.Context,
+	region string,
+	baseUrl string,
 	organizationID string,
 	token string,
 	limitCourses []string,
+	clientOptions ...client.Option,
 ) (*Connector, error) {
+	resolvedBaseUrl, err := client.ResolveBaseUrl(region, baseUrl)
+	if err != nil {
+		return nil, err
+	}
+
 	percipioClient, err := client.New(
 		ctx,
-		client.BaseApiUrl,
+		resolvedBaseUrl,
 		organizationID,
 		token,
+		clientOptions...,
 	)
 	if err != nil {
 		return nil, err
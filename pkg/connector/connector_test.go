@@ -0,0 +1,64 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-percipio/test"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectorCleanup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should read fresh users and catalog on every sync", func(t *testing.T) {
+		fixtures := test.FixturesServer()
+		defer fixtures.Close()
+		var emptied atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if emptied.Load() && (strings.Contains(request.URL.Path, "catalog-content") || strings.Contains(request.URL.Path, "users")) {
+				writer.Header().Set("Content-Type", "application/json")
+				writer.Header().Set(client.HeaderNameTotalCount, "0")
+				_, _ = writer.Write([]byte(`[]`))
+				return
+			}
+			fixtures.Config.Handler.ServeHTTP(writer, request)
+		}))
+		defer server.Close()
+
+		t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+		cb, err := New(ctx, "", server.URL, "mock", "token", nil)
+		require.Nil(t, err)
+		syncers := make(map[string]connectorbuilder.ResourceSyncer)
+		for _, syncer := range cb.ResourceSyncers(ctx) {
+			syncers[syncer.ResourceType(ctx).Id] = syncer
+		}
+
+		listCount := func(resourceTypeId string) int {
+			resources, _, _, err := syncers[resourceTypeId].List(ctx, organizationResourceID("mock"), &pagination.Token{})
+			require.Nil(t, err)
+			return len(resources)
+		}
+
+		require.Equal(t, 1, listCount(courseResourceType.Id))
+		require.NotZero(t, listCount(channelResourceType.Id))
+		require.Equal(t, 1, listCount(roleResourceType.Id))
+
+		emptied.Store(true)
+		require.Equal(t, 1, listCount(courseResourceType.Id), "the catalog is shared for the whole sync")
+
+		cb.Cleanup(ctx)
+		require.Equal(t, 0, listCount(courseResourceType.Id))
+		require.Equal(t, 0, listCount(assessmentResourceType.Id))
+		require.Equal(t, 0, listCount(channelResourceType.Id))
+		require.Equal(t, 0, listCount(journeyResourceType.Id))
+		require.Equal(t, 0, listCount(roleResourceType.Id))
+	})
+}
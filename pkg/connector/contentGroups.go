@@ -0,0 +1,258 @@
+package connector
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// contentGroupRef struct identifies a group a catalog item belongs to.
+// It is used by the `groupsOf` callback of a `contentGroupIndex` to report a course's channels or journeys.
+// It holds the group's ID and its display title.
+// This structure organizes the only information Percipio exposes about groups, which are known solely through catalog associations.
+// Instances are created by the `channelsOf` and `journeysOf` functions.
+type contentGroupRef struct {
+	id    string
+	title string
+}
+
+// contentGroup struct records what the connector knows about one group of catalog content.
+// It is used by the `contentGroupIndex` to list groups and compute roll-up grants.
+// It holds the group's display title and the IDs of the synced content that belongs to it.
+// This structure organizes group membership, which Percipio only exposes through each course's associations.
+// Instances are created by `build` while walking the catalog.
+type contentGroup struct {
+	title   string
+	members mapset.Set[string]
+}
+
+// contentGroupIndex struct is an in-memory index of groups discovered by walking the catalog.
+// It is used by the channel and journey builders, whose resources have no listing endpoint of their own.
+// It holds the shared content catalog, a callback returning the groups of a catalog item, and the index itself.
+// This structure organizes the deduplication of groups referenced by many courses across the catalog.
+// Instances are created by the `newContentGroupIndex` function.
+type contentGroupIndex struct {
+	catalog  *contentCatalog
+	groupsOf func(course client.Course, resource *v2.Resource) []contentGroupRef
+
+	mu       sync.Mutex
+	groups   map[string]*contentGroup
+	groupIds []string
+}
+
+// newContentGroupIndex function creates a new `contentGroupIndex`.
+// It implements the constructor shared by the channel and journey builders.
+// The function initializes the index with the shared content catalog and the callback selecting groups.
+// Which lets each builder decide which associations and which content types make up its groups.
+// This implementation leaves the index empty until it is first needed.
+func newContentGroupIndex(
+	catalog *contentCatalog,
+	groupsOf func(course client.Course, resource *v2.Resource) []contentGroupRef,
+) *contentGroupIndex {
+	return &contentGroupIndex{
+		catalog:  catalog,
+		groupsOf: groupsOf,
+	}
+}
+
+// build method groups the synced catalog items and records every group and its members.
+// It implements the group discovery shared by the `List` and `Grants` methods of group builders.
+// The method reads the shared catalog and adds each synced item to every group `groupsOf` returns for it.
+// Which is the only way to enumerate channels and journeys, since Percipio exposes them solely as course associations.
+// This implementation builds the index at most once and leaves the catalog traversal to the `contentCatalog`.
+func (i *contentGroupIndex) build(ctx context.Context) (annotations.Annotations, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.groupIds != nil {
+		return nil, nil
+	}
+
+	items, outputAnnotations, err := i.catalog.all(ctx)
+	if err != nil {
+		return outputAnnotations, err
+	}
+
+	groups := make(map[string]*contentGroup)
+	for _, item := range items {
+		for _, ref := range i.groupsOf(item.course, item.resource) {
+			if ref.id == "" {
+				continue
+			}
+			group, ok := groups[ref.id]
+			if !ok {
+				group = &contentGroup{
+					title:   ref.title,
+					members: mapset.NewThreadUnsafeSet[string](),
+				}
+				groups[ref.id] = group
+			}
+			group.members.Add(item.course.Id)
+		}
+	}
+
+	groupIds := make([]string, 0, len(groups))
+	for groupId := range groups {
+		groupIds = append(groupIds, groupId)
+	}
+	slices.Sort(groupIds)
+
+	ctxzap.Extract(ctx).Debug("built content group index", zap.Int("groups", len(groupIds)))
+	i.groups = groups
+	i.groupIds = groupIds
+	return outputAnnotations, nil
+}
+
+// reset method drops the group index.
+// It implements the `syncCache` interface used by the connector's `Cleanup`.
+// The method clears the index so the next call to `build` groups the catalog again.
+// Which keeps channels and journeys in step with the catalog once it is reset too.
+// This implementation keeps the catalog and the `groupsOf` callback.
+func (i *contentGroupIndex) reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.groups = nil
+	i.groupIds = nil
+}
+
+// list method returns a page of groups as `v2.Resource` objects.
+// It implements the `List` logic shared by the channel and journey builders.
+// The method builds the index on first use and returns the requested slice of groups, sorted by ID.
+// Which enables the baton-sdk to sync every group that contains at least one synced item.
+// This implementation uses the offset-based `client.ParseUserPaginationToken` and `client.GetUserNextToken` helpers over the in-memory index,
+// and only lists groups under the organization.
+func (i *contentGroupIndex) list(
+	ctx context.Context,
+	resourceType *v2.ResourceType,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	outputAnnotations, err := i.build(ctx)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	offset, limit, err := client.ParseUserPaginationToken(pToken)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	start := min(offset, len(i.groupIds))
+	end := min(offset+limit, len(i.groupIds))
+	outputResources := make([]*v2.Resource, 0, end-start)
+	for _, groupId := range i.groupIds[start:end] {
+		resource, err := resourceSdk.NewResource(
+			i.groups[groupId].title,
+			resourceType,
+			groupId,
+			resourceSdk.WithParentResourceID(parentResourceID),
+		)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		outputResources = append(outputResources, resource)
+	}
+
+	return outputResources, client.GetUserNextToken(ctx, offset, limit, len(i.groupIds)), outputAnnotations, nil
+}
+
+// members method returns the synced content IDs of a group.
+// It implements the membership lookup used when computing roll-up grants.
+// The method builds the index on first use and looks the group up by ID.
+// Which lets `Grants` work even if the SDK calls it on a fresh builder.
+// This implementation returns nil for a group that is not in the index.
+func (i *contentGroupIndex) members(ctx context.Context, groupId string) ([]string, annotations.Annotations, error) {
+	outputAnnotations, err := i.build(ctx)
+	if err != nil {
+		return nil, outputAnnotations, err
+	}
+
+	group, ok := i.groups[groupId]
+	if !ok {
+		return nil, outputAnnotations, nil
+	}
+	members := group.members.ToSlice()
+	slices.Sort(members)
+	return members, outputAnnotations, nil
+}
+
+// usersWithStatusOnAll function finds the users whose status on every given content item is accepted.
+// It implements the roll-up computation behind the channel and journey completion entitlements.
+// The function intersects, item by item, the users whose status in the `StatusesStore` satisfies `accept`.
+// Which derives group completion from the same report data as the course grants.
+// This implementation stops reading the store as soon as no user is left, and returns an empty set for no items.
+func usersWithStatusOnAll(
+	ctx context.Context,
+	store client.StatusesStore,
+	contentIds []string,
+	accept func(status string) bool,
+) (mapset.Set[string], error) {
+	var found mapset.Set[string]
+	for _, contentId := range contentIds {
+		statusesMap, err := store.Get(ctx, contentId)
+		if err != nil {
+			return nil, err
+		}
+
+		accepted := mapset.NewThreadUnsafeSet[string]()
+		for userId, status := range statusesMap {
+			if accept(status) {
+				accepted.Add(userId)
+			}
+		}
+
+		if found == nil {
+			found = accepted
+		} else {
+			found = found.Intersect(accepted)
+		}
+		if found.Cardinality() == 0 {
+			break
+		}
+	}
+
+	if found == nil {
+		return mapset.NewThreadUnsafeSet[string](), nil
+	}
+	return found, nil
+}
+
+// usersWithStatusOnAny function finds the users with any recorded activity on the given content items.
+// It implements the roll-up computation behind the journey enrollment entitlement.
+// The function unions the users present in the `StatusesStore` for each item.
+// Which treats any started, completed or attempted item as participation in the group.
+// This implementation ignores rows whose status could not be mapped.
+func usersWithStatusOnAny(ctx context.Context, store client.StatusesStore, contentIds []string) (mapset.Set[string], error) {
+	found := mapset.NewThreadUnsafeSet[string]()
+	for _, contentId := range contentIds {
+		statusesMap, err := store.Get(ctx, contentId)
+		if err != nil {
+			return nil, err
+		}
+		for userId, status := range statusesMap {
+			if status != client.StatusUnknown {
+				found.Add(userId)
+			}
+		}
+	}
+	return found, nil
+}
@@ -2,6 +2,7 @@ package connector
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/conductorone/baton-percipio/pkg/connector/client"
@@ -14,22 +15,25 @@ import (
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
+	assignedEntitlement   = "assigned"
 	completedEntitlement  = "completed"
 	inProgressEntitlement = "in_progress"
 )
 
 // courseBuilder struct is responsible for syncing course resources and their associated grants.
-// It is used by the connector to fetch and process all course and assessment data from the Percipio API.
-// It holds a reference to the API client, the course resource type descriptor, and a set of courses to limit the sync.
+// It is used by the connector to fetch and process all course data from the Percipio API.
+// It holds a reference to the API client, the course resource type descriptor, and the catalog shared with the other content builders.
 // This structure organizes the context needed for all course-related synchronization operations.
 // Instances are created by the `newCourseBuilder` function.
 type courseBuilder struct {
 	client       *client.Client
 	resourceType *v2.ResourceType
-	limitCourses mapset.Set[string]
+	catalog      *contentCatalog
 }
 
 // ResourceType method returns the resource type descriptor for courses.
@@ -41,18 +45,36 @@ func (o *courseBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 	return o.resourceType
 }
 
+// contentResourceType function selects the resource type for a catalog content item.
+// It implements the routing of catalog content to the course and assessment syncers.
+// The function returns `assessmentResourceType` for ASSESSMENT items and `courseResourceType` for everything else.
+// Which keeps "took the course" and "passed the assessment" apart in access reviews.
+// This implementation keys off the item's `percipioType`.
+func contentResourceType(course client.Course) *v2.ResourceType {
+	if course.ContentType.PercipioType == "ASSESSMENT" {
+		return assessmentResourceType
+	}
+	return courseResourceType
+}
+
 // courseResource function creates a new `v2.Resource` from a Percipio course object.
 // It implements the mapping from the provider's content data model to the baton-sdk's resource model.
-// The function filters out inactive or non-course/assessment content and constructs a display name before creating the resource.
+// The function filters out inactive content and content types outside `contentTypes`, and constructs a display name before creating the resource
+// as an assessment when its `percipioType` is ASSESSMENT, or as a course otherwise.
 // Which is the core transformation for converting raw course data into a standardized resource object that Baton can process.
 // This implementation returns `nil` for any content that should be skipped, which is handled by the caller.
-func courseResource(ctx context.Context, course client.Course, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+func courseResource(
+	ctx context.Context,
+	course client.Course,
+	parentResourceID *v2.ResourceId,
+	contentTypes mapset.Set[string],
+) (*v2.Resource, error) {
 	l := ctxzap.Extract(ctx)
 	if course.Lifecycle.Status == "INACTIVE" {
 		l.Debug("Skipping inactive course", zap.String("courseId", course.Id))
 		return nil, nil
 	}
-	if course.ContentType.PercipioType != "COURSE" && course.ContentType.PercipioType != "ASSESSMENT" {
+	if !contentTypes.Contains(course.ContentType.PercipioType) {
 		l.Debug("Skipping non-course content", zap.String("courseId", course.Id), zap.String("contentType", course.ContentType.PercipioType))
 		return nil, nil
 	}
@@ -86,7 +108,7 @@ func courseResource(ctx context.Context, course client.Course, parentResourceID
 
 	resource, err := resourceSdk.NewResource(
 		courseName,
-		courseResourceType,
+		contentResourceType(course),
 		course.Id,
 		resourceOpts...,
 	)
@@ -99,9 +121,9 @@ func courseResource(ctx context.Context, course client.Course, parentResourceID
 
 // List method fetches a page of courses and returns them as `v2.Resource` objects.
 // It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
-// The method calls the Percipio API to get a page of content, transforms each item into a resource, and returns the list along with a pagination token.
-// Which enables the baton-sdk to paginate through all course and assessment resources in the upstream system.
-// This implementation uses the `client.ParseContentPaginationToken` and `client.GetContentNextToken` functions to handle Percipio's non-standard pagination logic.
+// The method reads a page of the shared catalog and returns the courses in it along with a pagination token.
+// Which enables the baton-sdk to paginate through all course resources in the upstream system.
+// This implementation delegates to `listContent`, which skips assessments so they are synced by the assessment builder.
 func (o *courseBuilder) List(
 	ctx context.Context,
 	parentResourceID *v2.ResourceId,
@@ -112,17 +134,91 @@ func (o *courseBuilder) List(
 	annotations.Annotations,
 	error,
 ) {
+	return listContent(ctx, o.catalog, o.resourceType, parentResourceID, pToken)
+}
+
+// listContent function returns a page of the synced catalog items of one resource type.
+// It implements the listing shared by the course and assessment syncers.
+// The function reads the shared catalog, keeps the items of `resourceType`, and maps the requested slice of them to resources under `parentResourceID`.
+// Which lets courses and assessments be synced as separate resource types from a single catalog traversal.
+// This implementation uses the offset-based `client.ParseUserPaginationToken` and `client.GetUserNextToken` helpers over the in-memory catalog,
+// and only lists content under the organization.
+func listContent(
+	ctx context.Context,
+	catalog *contentCatalog,
+	resourceType *v2.ResourceType,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
 	l := ctxzap.Extract(ctx)
-	l.Debug("Starting Courses List", zap.String("token", pToken.Token))
+	l.Debug("Starting content list", zap.String("resourceType", resourceType.Id), zap.String("token", pToken.Token))
+
+	items, outputAnnotations, err := catalog.all(ctx)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	offset, limit, err := client.ParseUserPaginationToken(pToken)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	courses := make([]client.Course, 0)
+	for _, item := range items {
+		if item.resource.Id.ResourceType == resourceType.Id {
+			courses = append(courses, item.course)
+		}
+	}
+
+	start := min(offset, len(courses))
+	end := min(offset+limit, len(courses))
+	outputResources := make([]*v2.Resource, 0, end-start)
+	for _, course := range courses[start:end] {
+		resource, err := courseResource(ctx, course, parentResourceID, catalog.contentTypes)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		outputResources = append(outputResources, resource)
+	}
+
+	return outputResources, client.GetUserNextToken(ctx, offset, limit, len(courses)), outputAnnotations, nil
+}
 
-	outputResources := make([]*v2.Resource, 0)
+// fetchContentPage function fetches a page of catalog content items.
+// It implements the catalog traversal behind the `contentCatalog` shared by the content syncers.
+// The function calls the Percipio API to get a page of content and returns the items along with a pagination token.
+// Which keeps Percipio's two ways of reading the catalog behind a single call.
+// This implementation uses the `client.ParseContentPaginationToken` and `client.GetContentNextToken` functions to handle Percipio's non-standard pagination logic,
+// and the search endpoint instead when the sync is limited to specific content IDs, returning every limited item in a single page.
+func fetchContentPage(
+	ctx context.Context,
+	percipioClient *client.Client,
+	limitCourses mapset.Set[string],
+	pToken *pagination.Token,
+) (
+	[]client.Course,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	l := ctxzap.Extract(ctx)
+	outputCourses := make([]client.Course, 0)
 	var outputAnnotations annotations.Annotations
 
 	// If limitCourses is set, we use the search endpoint instead of paginating
-	if o.limitCourses != nil && o.limitCourses.Cardinality() > 0 {
-		courseIDs := o.limitCourses.ToSlice()
+	if limitCourses != nil && limitCourses.Cardinality() > 0 {
+		courseIDs := limitCourses.ToSlice()
 		for _, courseID := range courseIDs {
-			courses, ratelimitData, err := o.client.SearchContentByID(ctx, courseID)
+			courses, ratelimitData, err := percipioClient.SearchContentByID(ctx, courseID)
 			outputAnnotations.WithRateLimiting(ratelimitData)
 			if err != nil {
 				l.Warn("failed to find course by id", zap.Error(err), zap.String("courseID", courseID))
@@ -132,19 +228,12 @@ func (o *courseBuilder) List(
 			// The search endpoint can return multiple results, we need to find the exact match
 			for _, course := range courses {
 				if course.Id == courseID {
-					resource, err := courseResource(ctx, course, parentResourceID)
-					if err != nil {
-						return nil, "", nil, err
-					}
-					if resource == nil {
-						continue
-					}
-					outputResources = append(outputResources, resource)
+					outputCourses = append(outputCourses, course)
 				}
 			}
 		}
 
-		return outputResources, "", outputAnnotations, nil
+		return outputCourses, "", outputAnnotations, nil
 	}
 
 	offset, pagingRequestId, finalOffset, err := client.ParseContentPaginationToken(ctx, pToken)
@@ -152,7 +241,7 @@ func (o *courseBuilder) List(
 		return nil, "", nil, err
 	}
 
-	courses, newPagingRequestId, returnedFinalOffset, ratelimitData, err := o.client.GetCourses(
+	courses, newPagingRequestId, returnedFinalOffset, ratelimitData, err := percipioClient.GetCourses(
 		ctx,
 		offset,
 		1000,
@@ -181,17 +270,10 @@ func (o *courseBuilder) List(
 		return nil, "", outputAnnotations, err
 	}
 	for _, course := range courses {
-		if o.limitCourses != nil && !o.limitCourses.Contains(course.Id) {
+		if limitCourses != nil && !limitCourses.Contains(course.Id) {
 			continue
 		}
-		resource, err := courseResource(ctx, course, parentResourceID)
-		if err != nil {
-			return nil, "", nil, err
-		}
-		if resource == nil {
-			continue
-		}
-		outputResources = append(outputResources, resource)
+		outputCourses = append(outputCourses, course)
 	}
 
 	nextToken := client.GetContentNextToken(ctx, offset, 1000, finalOffset, newPagingRequestId)
@@ -203,14 +285,14 @@ func (o *courseBuilder) List(
 		)
 	}
 
-	return outputResources, nextToken, outputAnnotations, nil
+	return outputCourses, nextToken, outputAnnotations, nil
 }
 
 // Entitlements method returns the entitlements for a course resource.
 // It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
 // The method defines the 'assigned', 'completed', and 'in_progress' entitlements for a given course.
 // Which allows Baton to model the different types of relationships a user can have with a course.
-// This implementation returns a static list of three assignment entitlements.
+// This implementation returns a static list of three assignment entitlements, of which only 'assigned' can be provisioned.
 func (o *courseBuilder) Entitlements(
 	_ context.Context,
 	resource *v2.Resource,
@@ -222,6 +304,13 @@ func (o *courseBuilder) Entitlements(
 	error,
 ) {
 	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			assignedEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("Course %s %s", resource.DisplayName, assignedEntitlement)),
+			entitlement.WithDescription(fmt.Sprintf("Assigned course %s in Percipio", resource.DisplayName)),
+		),
 		entitlement.NewAssignmentEntitlement(
 			resource,
 			completedEntitlement,
@@ -242,13 +331,15 @@ func (o *courseBuilder) Entitlements(
 // Grants method fetches and returns the grants for a course resource.
 // It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
 // The method orchestrates a multi-step, asynchronous report generation process: it first requests a report,
-// then polls for its completion, and finally processes the report data from an in-memory cache to create grants.
+// then polls for its completion, and finally processes the report data from the statuses store to create grants.
 // Which is the only mechanism for determining user course entitlements in the Percipio API.
-// This implementation relies on the client's `StatusesStore` to retrieve the report data fetched by the report syncer.
+// This implementation relies on `loadLearningActivityReport` to make sure the report has been loaded into the client's `StatusesStore`,
+// returns the report grants with the first page, and pages through the course's assignments for the 'assigned' entitlement,
+// skipping them only when the assignment API is not found, so any other failure fails the sync instead of dropping assignments.
 func (o *courseBuilder) Grants(
 	ctx context.Context,
 	resource *v2.Resource,
-	_ *pagination.Token,
+	pToken *pagination.Token,
 ) (
 	[]*v2.Grant,
 	string,
@@ -256,46 +347,215 @@ func (o *courseBuilder) Grants(
 	error,
 ) {
 	var outputAnnotations annotations.Annotations
-	if o.client.ReportStatus.Status == "" {
-		ratelimitData, err := o.client.GenerateLearningActivityReport(ctx)
-		outputAnnotations.WithRateLimiting(ratelimitData)
+	offset, limit, err := client.ParseUserPaginationToken(pToken)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	if offset == 0 {
+		err = loadLearningActivityReport(ctx, o.client, &outputAnnotations)
 		if err != nil {
 			return nil, "", outputAnnotations, err
 		}
-	}
 
-	if o.client.ReportStatus.Status == "PENDING" || o.client.ReportStatus.Status == "IN_PROGRESS" {
-		ratelimitData, err := o.client.GetLearningActivityReport(ctx)
-		outputAnnotations.WithRateLimiting(ratelimitData)
+		statusesMap, err := o.client.StatusesStore.Get(ctx, resource.Id.Resource)
 		if err != nil {
 			return nil, "", outputAnnotations, err
 		}
+
+		for userId, status := range statusesMap {
+			principalId, err := resourceSdk.NewResourceID(userResourceType, userId)
+			if err != nil {
+				return nil, "", outputAnnotations, err
+			}
+			nextGrant := grant.NewGrant(resource, status, principalId)
+			grants = append(grants, nextGrant)
+		}
 	}
 
-	statusesMap := o.client.StatusesStore.Get(resource.Id.Resource)
+	assignments, total, ratelimitData, err := o.client.GetAssignments(ctx, resource.Id.Resource, "", offset, limit)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	switch {
+	case err == nil:
+	case status.Code(err) == codes.NotFound:
+		ctxzap.Extract(ctx).Warn("course assignments are not available", zap.String("courseId", resource.Id.Resource), zap.Error(err))
+		assignments, total = nil, 0
+	default:
+		return nil, "", outputAnnotations, err
+	}
 
-	grants := make([]*v2.Grant, 0)
-	for userId, status := range statusesMap {
-		principalId, err := resourceSdk.NewResourceID(userResourceType, userId)
+	for _, assignment := range assignments {
+		if assignment.ContentId != resource.Id.Resource {
+			continue
+		}
+		principalId, err := resourceSdk.NewResourceID(userResourceType, assignment.UserId)
 		if err != nil {
 			return nil, "", outputAnnotations, err
 		}
-		nextGrant := grant.NewGrant(resource, status, principalId)
-		grants = append(grants, nextGrant)
+		grants = append(grants, grant.NewGrant(resource, assignedEntitlement, principalId))
+	}
+
+	return grants, client.GetUserNextToken(ctx, offset, limit, total), outputAnnotations, nil
+}
+
+// assignmentTarget function validates a provisioning request for the 'assigned' entitlement.
+// It implements the argument checks shared by `Grant` and `Revoke`.
+// The function makes sure the principal is a user and the entitlement is a course's 'assigned' entitlement.
+// Which keeps completion and progress, which only learners can change, from being provisioned.
+// This implementation compares entitlement IDs, since synced grants do not carry the entitlement slug,
+// and returns the content ID and user ID to pass to the Percipio assignment API.
+func assignmentTarget(principal *v2.Resource, courseEntitlement *v2.Entitlement) (string, string, error) {
+	if principal.GetId().GetResourceType() != userResourceType.Id {
+		return "", "", status.Errorf(codes.InvalidArgument, "baton-percipio: only users can be assigned courses, got %s", principal.GetId().GetResourceType())
+	}
+	if courseEntitlement.GetId() != entitlement.NewEntitlementID(courseEntitlement.GetResource(), assignedEntitlement) {
+		return "", "", status.Errorf(codes.InvalidArgument, "baton-percipio: only the %s entitlement can be provisioned, got %s", assignedEntitlement, courseEntitlement.GetId())
+	}
+	return courseEntitlement.GetResource().GetId().GetResource(), principal.GetId().GetResource(), nil
+}
+
+// Grant method assigns a course to a user in Percipio.
+// It implements the `Grant` method required by the `connectorbuilder.ResourceProvisionerV2` interface.
+// The method creates a Percipio content assignment for the user, due after `assignment-due-days` when configured.
+// Which lets access requests push mandatory training directly into Percipio.
+// This implementation checks the current assignments past the HTTP cache and does not create a second assignment when the user is already assigned the course.
+func (o *courseBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	courseEntitlement *v2.Entitlement,
+) (
+	[]*v2.Grant,
+	annotations.Annotations,
+	error,
+) {
+	var outputAnnotations annotations.Annotations
+	contentId, userId, err := assignmentTarget(principal, courseEntitlement)
+	if err != nil {
+		return nil, nil, err
+	}
+	grants := []*v2.Grant{
+		grant.NewGrant(courseEntitlement.GetResource(), assignedEntitlement, principal.GetId()),
+	}
+
+	existing, _, ratelimitData, err := o.client.GetAssignments(client.Uncached(ctx), contentId, userId, 0, 1)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return nil, outputAnnotations, err
+	}
+	if len(existing) > 0 {
+		outputAnnotations.Update(&v2.GrantAlreadyExists{})
+		return grants, outputAnnotations, nil
+	}
+
+	_, ratelimitData, err = o.client.CreateAssignment(ctx, contentId, userId)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return nil, outputAnnotations, err
+	}
+	return grants, outputAnnotations, nil
+}
+
+// Revoke method removes a course assignment from a user in Percipio.
+// It implements the `Revoke` method required by the `connectorbuilder.ResourceProvisionerV2` interface.
+// The method looks up every assignment of the course to the user and deletes each of them.
+// Which takes the training off the learner's mandatory list when access is removed.
+// This implementation lists the current assignments past the HTTP cache, reports the grant as already revoked when no assignment exists,
+// and ignores assignments removed concurrently.
+func (o *courseBuilder) Revoke(
+	ctx context.Context,
+	courseGrant *v2.Grant,
+) (
+	annotations.Annotations,
+	error,
+) {
+	var outputAnnotations annotations.Annotations
+	contentId, userId, err := assignmentTarget(courseGrant.GetPrincipal(), courseGrant.GetEntitlement())
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]client.Assignment, 0)
+	for offset := 0; ; offset += client.PageSizeDefault {
+		page, total, ratelimitData, err := o.client.GetAssignments(client.Uncached(ctx), contentId, userId, offset, client.PageSizeDefault)
+		outputAnnotations.WithRateLimiting(ratelimitData)
+		if err != nil {
+			return outputAnnotations, err
+		}
+		assignments = append(assignments, page...)
+		if len(page) == 0 || offset+client.PageSizeDefault >= total {
+			break
+		}
+	}
+
+	revoked := false
+	for _, assignment := range assignments {
+		if assignment.ContentId != contentId || assignment.UserId != userId {
+			continue
+		}
+		ratelimitData, err := o.client.DeleteAssignment(ctx, assignment.Id)
+		outputAnnotations.WithRateLimiting(ratelimitData)
+		if status.Code(err) == codes.NotFound {
+			continue
+		}
+		if err != nil {
+			return outputAnnotations, err
+		}
+		revoked = true
+	}
+
+	if !revoked {
+		outputAnnotations.Update(&v2.GrantAlreadyRevoked{})
+	}
+	return outputAnnotations, nil
+}
+
+// loadLearningActivityReport function makes sure the learning activity report has been loaded into the client's statuses store.
+// It implements the report orchestration shared by every syncer whose grants come from the report.
+// The function requests or resumes a report on first use, polls it until it is loaded, and records rate limit data on `outputAnnotations`.
+// Which lets courses and assessments share one report per sync instead of each requesting their own.
+// This implementation resumes a persisted in-flight report when possible, and regenerates the report once if it has expired.
+func loadLearningActivityReport(
+	ctx context.Context,
+	percipioClient *client.Client,
+	outputAnnotations *annotations.Annotations,
+) error {
+	if percipioClient.ReportStatus.Status == "" && !percipioClient.ResumeLearningActivityReport(ctx) {
+		ratelimitData, err := percipioClient.GenerateLearningActivityReport(ctx)
+		outputAnnotations.WithRateLimiting(ratelimitData)
+		if err != nil {
+			return err
+		}
+	}
+
+	if percipioClient.ReportStatus.Status != "PENDING" && percipioClient.ReportStatus.Status != "IN_PROGRESS" {
+		return nil
 	}
 
-	return grants, "", outputAnnotations, nil
+	ratelimitData, err := percipioClient.GetLearningActivityReport(ctx)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if errors.Is(err, client.ErrReportExpired) {
+		ctxzap.Extract(ctx).Warn("learning activity report is no longer available, generating a new one", zap.Error(err))
+		ratelimitData, err = percipioClient.GenerateLearningActivityReport(ctx)
+		outputAnnotations.WithRateLimiting(ratelimitData)
+		if err != nil {
+			return err
+		}
+		ratelimitData, err = percipioClient.GetLearningActivityReport(ctx)
+		outputAnnotations.WithRateLimiting(ratelimitData)
+	}
+	return err
 }
 
 // newCourseBuilder function creates a new `courseBuilder`.
 // It implements the constructor for the course resource syncer.
-// The function initializes a `courseBuilder` with an API client, the course resource type, and a set of courses to limit the sync.
+// The function initializes a `courseBuilder` with an API client, the course resource type, and the shared content catalog.
 // Which provides a configured syncer ready to be used by the main connector.
 // This implementation sets up the builder with its required dependencies.
-func newCourseBuilder(client *client.Client, limitCourses mapset.Set[string]) *courseBuilder {
+func newCourseBuilder(client *client.Client, catalog *contentCatalog) *courseBuilder {
 	return &courseBuilder{
 		client:       client,
 		resourceType: courseResourceType,
-		limitCourses: limitCourses,
+		catalog:      catalog,
 	}
 }
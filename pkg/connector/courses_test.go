@@ -2,15 +2,27 @@ package connector
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/conductorone/baton-percipio/pkg/connector/client"
 	"github.com/conductorone/baton-percipio/test"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestCoursesList(t *testing.T) {
@@ -28,15 +40,15 @@ func TestCoursesList(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	t.Run("should get all courses with pagination", func(t *testing.T) {
-		c := newCourseBuilder(percipioClient, nil)
+	t.Run("should get every distinct course with pagination", func(t *testing.T) {
+		c := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil))
 		resources := make([]*v2.Resource, 0)
 		pToken := pagination.Token{
 			Token: "",
 			Size:  1,
 		}
 		for {
-			nextResources, nextToken, listAnnotations, err := c.List(ctx, nil, &pToken)
+			nextResources, nextToken, listAnnotations, err := c.List(ctx, organizationResourceID("mock"), &pToken)
 			resources = append(resources, nextResources...)
 
 			require.Nil(t, err)
@@ -49,16 +61,56 @@ func TestCoursesList(t *testing.T) {
 		}
 
 		require.NotNil(t, resources)
-		require.Len(t, resources, 3)
+		require.Len(t, resources, 1)
 		require.NotEmpty(t, resources[0].Id)
 	})
 
+	t.Run("should walk the catalog once for every content builder", func(t *testing.T) {
+		fixtures := test.FixturesServer()
+		defer fixtures.Close()
+		var catalogRequests atomic.Int32
+		countingServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if strings.Contains(request.URL.Path, "catalog-content") {
+				catalogRequests.Add(1)
+			}
+			fixtures.Config.Handler.ServeHTTP(writer, request)
+		}))
+		defer countingServer.Close()
+
+		t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+		countingClient, err := client.New(ctx, countingServer.URL, "mock", "token")
+		require.Nil(t, err)
+		catalog := newContentCatalog(countingClient, nil)
+
+		courses, _, _, err := newCourseBuilder(countingClient, catalog).List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, courses, 1)
+		pages := catalogRequests.Load()
+		require.Positive(t, pages)
+
+		assessments, _, _, err := newAssessmentBuilder(countingClient, catalog).List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, assessments, 1)
+
+		channels := newChannelBuilder(countingClient, catalog)
+		channelResources, _, _, err := channels.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		require.NotEmpty(t, channelResources)
+		_, _, _, err = channels.Grants(ctx, channelResources[0], &pagination.Token{})
+		require.Nil(t, err)
+
+		_, _, _, err = newJourneyBuilder(countingClient, catalog).List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+
+		require.Equal(t, pages, catalogRequests.Load())
+	})
+
 	t.Run("should get limited courses using the search endpoint", func(t *testing.T) {
 		limitCourseID := "1a3a3f54-b601-4d45-a234-038c980ee20f"
 		limitCourses := mapset.NewSet(limitCourseID)
-		c := newCourseBuilder(percipioClient, limitCourses)
+		c := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, limitCourses))
 
-		resources, nextToken, listAnnotations, err := c.List(ctx, nil, &pagination.Token{})
+		resources, nextToken, listAnnotations, err := c.List(ctx, organizationResourceID("mock"), &pagination.Token{})
 		require.Nil(t, err)
 		test.AssertNoRatelimitAnnotations(t, listAnnotations)
 		require.Empty(t, nextToken, "next token should be empty when searching by id")
@@ -70,8 +122,34 @@ func TestCoursesList(t *testing.T) {
 		assert.Equal(t, "Case Studies: Successful Data Privacy Implementations", resources[0].DisplayName)
 	})
 
+	t.Run("should only keep configured content types", func(t *testing.T) {
+		videoClient, err := client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			client.WithContentTypes([]string{"Video"}),
+		)
+		require.Nil(t, err)
+
+		c := newCourseBuilder(videoClient, newContentCatalog(videoClient, nil))
+		video, err := courseResource(ctx, client.Course{
+			Id:          "00000000-0000-0000-0000-000000000001",
+			ContentType: client.ContentType{PercipioType: "VIDEO"},
+		}, nil, c.catalog.contentTypes)
+		require.Nil(t, err)
+		require.NotNil(t, video)
+
+		course, err := courseResource(ctx, client.Course{
+			Id:          "00000000-0000-0000-0000-000000000000",
+			ContentType: client.ContentType{PercipioType: "COURSE"},
+		}, nil, c.catalog.contentTypes)
+		require.Nil(t, err)
+		require.Nil(t, course)
+	})
+
 	t.Run("should list grants", func(t *testing.T) {
-		c := newCourseBuilder(percipioClient, nil)
+		c := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil))
 		course, _ := courseResource(ctx, client.Course{
 			Id: "00000000-0000-0000-0000-000000000000",
 			ContentType: client.ContentType{
@@ -79,7 +157,7 @@ func TestCoursesList(t *testing.T) {
 				Category:     "COURSE",
 				DisplayLabel: "Course",
 			},
-		}, nil)
+		}, nil, c.catalog.contentTypes)
 		grants := make([]*v2.Grant, 0)
 		pToken := pagination.Token{
 			Token: "",
@@ -98,4 +176,329 @@ func TestCoursesList(t *testing.T) {
 		}
 		require.Len(t, grants, 1)
 	})
+
+	t.Run("should resume a persisted report job", func(t *testing.T) {
+		stateDir := t.TempDir()
+		resumingClient, err := client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			client.WithReportJobStore(stateDir),
+		)
+		require.Nil(t, err)
+
+		jobs := client.NewReportJobStore(stateDir, "mock")
+		err = jobs.Save(client.ReportJob{
+			Id:          "11111111-1111-1111-1111-111111111111",
+			Status:      "IN_PROGRESS",
+			RequestedAt: time.Now(),
+		})
+		require.Nil(t, err)
+
+		c := newCourseBuilder(resumingClient, newContentCatalog(resumingClient, nil))
+		course, _ := courseResource(ctx, client.Course{
+			Id: "00000000-0000-0000-0000-000000000000",
+			ContentType: client.ContentType{
+				PercipioType: "COURSE",
+			},
+		}, nil, c.catalog.contentTypes)
+		grants, _, _, err := c.Grants(ctx, course, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+		require.Equal(t, "11111111-1111-1111-1111-111111111111", resumingClient.ReportStatus.Id)
+
+		job, err := jobs.Load()
+		require.Nil(t, err)
+		require.Nil(t, job, "consumed report job should be cleared")
+	})
+
+	t.Run("should record a watermark for incremental syncs", func(t *testing.T) {
+		stateDir := t.TempDir()
+		incrementalClient, err := client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			client.WithReportJobStore(stateDir),
+			client.WithIncrementalSync(24*time.Hour),
+		)
+		require.Nil(t, err)
+
+		c := newCourseBuilder(incrementalClient, newContentCatalog(incrementalClient, nil))
+		course, _ := courseResource(ctx, client.Course{
+			Id: "00000000-0000-0000-0000-000000000000",
+			ContentType: client.ContentType{
+				PercipioType: "COURSE",
+			},
+		}, nil, c.catalog.contentTypes)
+		grants, _, _, err := c.Grants(ctx, course, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+
+		watermark, err := client.NewReportJobStore(stateDir, "mock").LoadWatermark()
+		require.Nil(t, err)
+		require.NotNil(t, watermark)
+		require.False(t, watermark.End.IsZero())
+		require.Equal(t, watermark.End, watermark.FullSyncAt)
+	})
+
+	t.Run("should merge a time-sliced report", func(t *testing.T) {
+		slicedClient, err := client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			client.WithReportSlicing(4, 2),
+		)
+		require.Nil(t, err)
+
+		c := newCourseBuilder(slicedClient, newContentCatalog(slicedClient, nil))
+		course, _ := courseResource(ctx, client.Course{
+			Id: "00000000-0000-0000-0000-000000000000",
+			ContentType: client.ContentType{
+				PercipioType: "COURSE",
+			},
+		}, nil, c.catalog.contentTypes)
+		grants, _, _, err := c.Grants(ctx, course, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+		require.Equal(t, "COMPLETED", slicedClient.ReportStatus.Status)
+	})
+
+	t.Run("should sync course assignments", func(t *testing.T) {
+		c := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		course, _ := courseResource(ctx, client.Course{
+			Id: "1a3a3f54-b601-4d45-a234-038c980ee20f",
+			ContentType: client.ContentType{
+				PercipioType: "COURSE",
+			},
+		}, nil, c.catalog.contentTypes)
+
+		entitlements, _, _, err := c.Entitlements(ctx, course, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 3)
+
+		grants, _, _, err := c.Grants(ctx, course, &pagination.Token{})
+		require.Nil(t, err)
+
+		assigned := make([]string, 0)
+		for _, courseGrant := range grants {
+			if courseGrant.Entitlement.Id == entitlement.NewEntitlementID(course, assignedEntitlement) {
+				assigned = append(assigned, courseGrant.Principal.Id.Resource)
+			}
+		}
+		require.Equal(t, []string{"00000000-0000-0000-0000-000000000001"}, assigned)
+	})
+
+	t.Run("should only skip course assignments when they are not found", func(t *testing.T) {
+		for _, testCase := range []struct {
+			statusCode int
+			expected   codes.Code
+		}{
+			{http.StatusNotFound, codes.OK},
+			{http.StatusBadRequest, codes.InvalidArgument},
+			{http.StatusForbidden, codes.PermissionDenied},
+		} {
+			fixtures := test.FixturesServer()
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				if strings.Contains(request.URL.Path, "assignments") {
+					writer.WriteHeader(testCase.statusCode)
+					return
+				}
+				fixtures.Config.Handler.ServeHTTP(writer, request)
+			}))
+
+			percipioClient, err := client.New(ctx, server.URL, "mock", "token")
+			require.Nil(t, err)
+			c := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+			course, _ := courseResource(ctx, client.Course{
+				Id: "1a3a3f54-b601-4d45-a234-038c980ee20f",
+				ContentType: client.ContentType{
+					PercipioType: "COURSE",
+				},
+			}, nil, c.catalog.contentTypes)
+
+			grants, _, _, err := c.Grants(ctx, course, &pagination.Token{})
+			require.Equal(t, testCase.expected, status.Code(err), testCase.statusCode)
+			for _, courseGrant := range grants {
+				require.NotEqual(t, entitlement.NewEntitlementID(course, assignedEntitlement), courseGrant.Entitlement.Id)
+			}
+
+			server.Close()
+			fixtures.Close()
+		}
+	})
+}
+
+func TestCourseProvisioning(t *testing.T) {
+	ctx := context.Background()
+
+	newAssignmentsServer := func(existing []client.Assignment, requests *[]string, created *client.Assignment) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			*requests = append(*requests, request.Method+" "+request.URL.Path)
+			writer.Header().Set("Content-Type", "application/json")
+			writer.Header().Set(client.HeaderNameTotalCount, strconv.Itoa(len(existing)))
+			switch request.Method {
+			case http.MethodGet:
+				_ = json.NewEncoder(writer).Encode(existing)
+			case http.MethodPost:
+				_ = json.NewDecoder(request.Body).Decode(created)
+				created.Id = "00000000-0000-0000-0000-0000000000a2"
+				_ = json.NewEncoder(writer).Encode(created)
+			default:
+				writer.WriteHeader(http.StatusNoContent)
+			}
+		}))
+	}
+
+	user, err := userResource(client.User{Id: "00000000-0000-0000-0000-000000000001"}, nil)
+	require.Nil(t, err)
+	course, err := resourceSdk.NewResource("Course", courseResourceType, "00000000-0000-0000-0000-000000000000")
+	require.Nil(t, err)
+	assigned := entitlement.NewAssignmentEntitlement(course, assignedEntitlement)
+
+	t.Run("should create an assignment with a due date", func(t *testing.T) {
+		var requests []string
+		var created client.Assignment
+		server := newAssignmentsServer(nil, &requests, &created)
+		defer server.Close()
+
+		percipioClient, err := client.New(ctx, server.URL, "mock", "token", client.WithAssignmentDueDays(14))
+		require.Nil(t, err)
+
+		grants, grantAnnotations, err := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil)).Grant(ctx, user, assigned)
+		require.Nil(t, err)
+		require.False(t, grantAnnotations.Contains(&v2.GrantAlreadyExists{}))
+		require.Len(t, grants, 1)
+		require.Equal(t, assigned.Id, grants[0].Entitlement.Id)
+		require.Equal(t, []string{
+			"GET /content-assignment/v1/organizations/mock/assignments",
+			"POST /content-assignment/v1/organizations/mock/assignments",
+		}, requests)
+		require.Equal(t, "00000000-0000-0000-0000-000000000000", created.ContentId)
+		require.Equal(t, "00000000-0000-0000-0000-000000000001", created.UserId)
+		require.Equal(t, time.Now().UTC().Add(14*24*time.Hour).Format("2006-01-02"), created.DueDate)
+	})
+
+	t.Run("should not assign a course twice", func(t *testing.T) {
+		var requests []string
+		server := newAssignmentsServer([]client.Assignment{{
+			Id:        "00000000-0000-0000-0000-0000000000a1",
+			ContentId: "00000000-0000-0000-0000-000000000000",
+			UserId:    "00000000-0000-0000-0000-000000000001",
+		}}, &requests, &client.Assignment{})
+		defer server.Close()
+
+		percipioClient, err := client.New(ctx, server.URL, "mock", "token")
+		require.Nil(t, err)
+
+		grants, grantAnnotations, err := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil)).Grant(ctx, user, assigned)
+		require.Nil(t, err)
+		require.True(t, grantAnnotations.Contains(&v2.GrantAlreadyExists{}))
+		require.Len(t, grants, 1)
+		require.Len(t, requests, 1)
+	})
+
+	t.Run("should only provision the assigned entitlement to users", func(t *testing.T) {
+		percipioClient, err := client.New(ctx, "http://127.0.0.1:0", "mock", "token")
+		require.Nil(t, err)
+		c := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+
+		_, _, err = c.Grant(ctx, user, entitlement.NewAssignmentEntitlement(course, completedEntitlement))
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, _, err = c.Grant(ctx, course, assigned)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("should delete assignments on revoke", func(t *testing.T) {
+		var requests []string
+		server := newAssignmentsServer([]client.Assignment{{
+			Id:        "00000000-0000-0000-0000-0000000000a1",
+			ContentId: "00000000-0000-0000-0000-000000000000",
+			UserId:    "00000000-0000-0000-0000-000000000001",
+		}}, &requests, &client.Assignment{})
+		defer server.Close()
+
+		percipioClient, err := client.New(ctx, server.URL, "mock", "token")
+		require.Nil(t, err)
+
+		revokeAnnotations, err := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil)).Revoke(ctx, grant.NewGrant(course, assignedEntitlement, user.Id))
+		require.Nil(t, err)
+		require.False(t, revokeAnnotations.Contains(&v2.GrantAlreadyRevoked{}))
+		require.Equal(t, []string{
+			"GET /content-assignment/v1/organizations/mock/assignments",
+			"DELETE /content-assignment/v1/organizations/mock/assignments/00000000-0000-0000-0000-0000000000a1",
+		}, requests)
+	})
+
+	t.Run("should report a missing assignment as already revoked", func(t *testing.T) {
+		var requests []string
+		server := newAssignmentsServer(nil, &requests, &client.Assignment{})
+		defer server.Close()
+
+		percipioClient, err := client.New(ctx, server.URL, "mock", "token")
+		require.Nil(t, err)
+
+		revokeAnnotations, err := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil)).Revoke(ctx, grant.NewGrant(course, assignedEntitlement, user.Id))
+		require.Nil(t, err)
+		require.True(t, revokeAnnotations.Contains(&v2.GrantAlreadyRevoked{}))
+	})
+
+	t.Run("should read current assignments when revoking and granting in sequence", func(t *testing.T) {
+		assignments := []client.Assignment{{
+			Id:        "00000000-0000-0000-0000-0000000000a1",
+			ContentId: "00000000-0000-0000-0000-000000000000",
+			UserId:    "00000000-0000-0000-0000-000000000001",
+		}}
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			switch request.Method {
+			case http.MethodGet:
+				writer.Header().Set(client.HeaderNameTotalCount, strconv.Itoa(len(assignments)))
+				_ = json.NewEncoder(writer).Encode(assignments)
+			case http.MethodPost:
+				var created client.Assignment
+				_ = json.NewDecoder(request.Body).Decode(&created)
+				created.Id = "00000000-0000-0000-0000-0000000000a2"
+				assignments = append(assignments, created)
+				_ = json.NewEncoder(writer).Encode(created)
+			default:
+				for i, assignment := range assignments {
+					if strings.HasSuffix(request.URL.Path, "/"+assignment.Id) {
+						assignments = append(assignments[:i], assignments[i+1:]...)
+						writer.WriteHeader(http.StatusNoContent)
+						return
+					}
+				}
+				writer.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		percipioClient, err := client.New(ctx, server.URL, "mock", "token")
+		require.Nil(t, err)
+		c := newCourseBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		assignedGrant := grant.NewGrant(course, assignedEntitlement, user.Id)
+
+		_, grantAnnotations, err := c.Grant(ctx, user, assigned)
+		require.Nil(t, err)
+		require.True(t, grantAnnotations.Contains(&v2.GrantAlreadyExists{}))
+
+		revokeAnnotations, err := c.Revoke(ctx, assignedGrant)
+		require.Nil(t, err)
+		require.False(t, revokeAnnotations.Contains(&v2.GrantAlreadyRevoked{}))
+		require.Empty(t, assignments)
+
+		_, grantAnnotations, err = c.Grant(ctx, user, assigned)
+		require.Nil(t, err)
+		require.False(t, grantAnnotations.Contains(&v2.GrantAlreadyExists{}))
+		require.Len(t, assignments, 1)
+
+		revokeAnnotations, err = c.Revoke(ctx, assignedGrant)
+		require.Nil(t, err)
+		require.False(t, revokeAnnotations.Contains(&v2.GrantAlreadyRevoked{}))
+		require.Empty(t, assignments)
+	})
 }
@@ -0,0 +1,183 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	mapset "github.com/deckarep/golang-set/v2"
+)
+
+const enrolledEntitlement = "enrolled"
+
+// journeyBuilder struct is responsible for syncing journey resources and their progress-derived grants.
+// It is used by the connector to expose Percipio Journeys, the guided learning paths built from catalog content.
+// It holds a reference to the API client, the journey resource type descriptor, and an index of journeys built from the catalog.
+// This structure organizes the context needed for all journey-related synchronization operations.
+// Instances are created by the `newJourneyBuilder` function.
+type journeyBuilder struct {
+	client       *client.Client
+	resourceType *v2.ResourceType
+	index        *contentGroupIndex
+}
+
+// ResourceType method returns the resource type descriptor for journeys.
+// It implements the `ResourceType` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method returns the static `journeyResourceType` object defined for this connector.
+// Which informs the baton-sdk about the type of resource this syncer is responsible for.
+// This implementation returns a pre-defined object.
+func (o *journeyBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// journeysOf function returns the journeys a synced catalog item belongs to.
+// It implements the `groupsOf` callback of the journey index.
+// The function maps each of the item's journey associations to a `contentGroupRef`, titled after the journey or its ID.
+// Which deduplicates journeys referenced by many courses into a single resource.
+// This implementation includes every synced content type, since journeys mix courses, assessments and other content.
+func journeysOf(course client.Course, _ *v2.Resource) []contentGroupRef {
+	refs := make([]contentGroupRef, 0, len(course.Associations.Journeys))
+	for _, journey := range course.Associations.Journeys {
+		title := journey.Title
+		if title == "" {
+			title = journey.Id
+		}
+		refs = append(refs, contentGroupRef{
+			id:    journey.Id,
+			title: title,
+		})
+	}
+	return refs
+}
+
+// isFinishedStatus function reports whether a normalized status counts as finishing a piece of content.
+// It implements the completion rule for journeys, which mix courses and assessments.
+// The function accepts completed courses and passed assessments.
+// Which keeps a failed assessment from completing a journey.
+// This implementation treats every other status as unfinished.
+func isFinishedStatus(status string) bool {
+	return status == client.StatusCompleted || status == client.StatusPassed
+}
+
+// List method returns a page of journeys as `v2.Resource` objects.
+// It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method builds the journey index on first use and returns the requested slice of journeys, sorted by ID.
+// Which enables the baton-sdk to sync every journey that contains at least one synced item.
+// This implementation delegates to the shared `contentGroupIndex`.
+func (o *journeyBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	pToken *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return o.index.list(ctx, o.resourceType, parentResourceID, pToken)
+}
+
+// Entitlements method returns the entitlements for a journey resource.
+// It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method defines 'enrolled' and 'completed' entitlements for a given journey.
+// Which allows Baton to review who has started and who has finished a learning path.
+// This implementation returns a static list of two assignment entitlements.
+func (o *journeyBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			enrolledEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("Journey %s %s", resource.DisplayName, enrolledEntitlement)),
+			entitlement.WithDescription(fmt.Sprintf("Has activity in journey %s in Percipio", resource.DisplayName)),
+		),
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			completedEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("Journey %s %s", resource.DisplayName, completedEntitlement)),
+			entitlement.WithDescription(fmt.Sprintf("Completed all content in journey %s in Percipio", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// Grants method computes the progress-derived grants for a journey resource.
+// It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method loads the learning activity report, grants 'enrolled' to users with activity on any item of the journey,
+// and 'completed' to users who completed or passed every item.
+// Which surfaces journey completion, such as an onboarding path, as a reviewable grant.
+// This implementation grants a user who completed the journey both entitlements.
+func (o *journeyBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	members, outputAnnotations, err := o.index.members(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	err = loadLearningActivityReport(ctx, o.client, &outputAnnotations)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	enrolled, err := usersWithStatusOnAny(ctx, o.client.StatusesStore, members)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	completed, err := usersWithStatusOnAll(ctx, o.client.StatusesStore, members, isFinishedStatus)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	grants := make([]*v2.Grant, 0, enrolled.Cardinality()+completed.Cardinality())
+	for slug, userIds := range map[string]mapset.Set[string]{
+		enrolledEntitlement:  enrolled,
+		completedEntitlement: completed,
+	} {
+		for _, userId := range userIds.ToSlice() {
+			principalId, err := resourceSdk.NewResourceID(userResourceType, userId)
+			if err != nil {
+				return nil, "", outputAnnotations, err
+			}
+			grants = append(grants, grant.NewGrant(resource, slug, principalId))
+		}
+	}
+
+	return grants, "", outputAnnotations, nil
+}
+
+// newJourneyBuilder function creates a new `journeyBuilder`.
+// It implements the constructor for the journey resource syncer.
+// The function initializes a `journeyBuilder` with an API client, the journey resource type, and a journey index over the shared content catalog.
+// Which provides a configured syncer ready to be used by the main connector.
+// This implementation leaves the journey index empty until it is first needed.
+func newJourneyBuilder(client *client.Client, catalog *contentCatalog) *journeyBuilder {
+	return &journeyBuilder{
+		client:       client,
+		resourceType: journeyResourceType,
+		index:        newContentGroupIndex(catalog, journeysOf),
+	}
+}
@@ -0,0 +1,84 @@
+package connector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-percipio/test"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJourneys(t *testing.T) {
+	ctx := context.Background()
+	server := test.FixturesServer()
+	defer server.Close()
+
+	percipioClient, err := client.New(
+		ctx,
+		server.URL,
+		"mock",
+		"token",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should list journeys deduplicated across courses", func(t *testing.T) {
+		j := newJourneyBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		resources, nextToken, listAnnotations, err := j.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, listAnnotations)
+		require.Empty(t, nextToken)
+		require.Len(t, resources, 1)
+		require.Equal(t, journeyResourceType.Id, resources[0].Id.ResourceType)
+		require.Equal(t, "00000000-0000-0000-0000-0000000000b1", resources[0].Id.Resource)
+		require.Equal(t, "Engineering Onboarding", resources[0].DisplayName)
+
+		members, _, err := j.index.members(ctx, resources[0].Id.Resource)
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"00000000-0000-0000-0000-000000000000",
+			"00000000-0000-0000-0000-0000000000a1",
+		}, members)
+	})
+
+	t.Run("should grant enrolled and completed from member progress", func(t *testing.T) {
+		j := newJourneyBuilder(percipioClient, newContentCatalog(percipioClient, nil))
+		resources, _, _, err := j.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, resources, 1)
+		journey := resources[0]
+
+		grantsByUser := func() map[string][]string {
+			grants, _, _, err := j.Grants(ctx, journey, &pagination.Token{})
+			require.Nil(t, err)
+			found := make(map[string][]string)
+			for _, journeyGrant := range grants {
+				userId := journeyGrant.Principal.Id.Resource
+				found[userId] = append(found[userId], journeyGrant.Entitlement.Id)
+			}
+			return found
+		}
+
+		enrolledId := entitlement.NewEntitlementID(journey, enrolledEntitlement)
+		completedId := entitlement.NewEntitlementID(journey, completedEntitlement)
+		require.Equal(t, map[string][]string{
+			"00000000-0000-0000-0000-000000000001": {enrolledId},
+			"00000000-0000-0000-0000-000000000002": {enrolledId},
+		}, grantsByUser())
+
+		err = percipioClient.StatusesStore.Add(ctx, &client.ReportEntry{
+			ContentUUID: "00000000-0000-0000-0000-000000000000",
+			UserUUID:    "00000000-0000-0000-0000-000000000002",
+			Status:      "Completed",
+		})
+		require.Nil(t, err)
+
+		found := grantsByUser()
+		require.Equal(t, []string{enrolledId}, found["00000000-0000-0000-0000-000000000001"])
+		require.ElementsMatch(t, []string{enrolledId, completedId}, found["00000000-0000-0000-0000-000000000002"])
+	})
+}
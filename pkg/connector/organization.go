@@ -0,0 +1,261 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// licenseEntitlement struct describes a paid seat or privileged capability of the Percipio organization.
+// It is used by the organization builder to declare entitlements and to decide which users hold them.
+// It holds the entitlement slug, its display name and description, and a predicate reading the matching user flag.
+// This structure organizes the license flags of `client.User` so entitlements and grants cannot drift apart.
+// Instances are declared in the `licenseEntitlements` table.
+type licenseEntitlement struct {
+	slug        string
+	displayName string
+	description string
+	holds       func(user client.User) bool
+}
+
+// licenseEntitlements lists the license and capability entitlements of the Percipio organization.
+// It is used by the organization builder's `Entitlements` and `Grants` methods.
+// It holds one entry per license or privilege flag exposed on Percipio users.
+// This variable keeps the mapping from user flags to entitlements in one place.
+// The instance is a fixed table and is never mutated.
+var licenseEntitlements = []licenseEntitlement{
+	{
+		slug:        "coaching",
+		displayName: "Coaching",
+		description: "Has a Percipio coaching seat",
+		holds:       func(user client.User) bool { return user.HasCoaching },
+	},
+	{
+		slug:        "enterprise_coaching",
+		displayName: "Enterprise Coaching",
+		description: "Has a Percipio enterprise coaching seat",
+		holds:       func(user client.User) bool { return user.HasEnterpriseCoaching },
+	},
+	{
+		slug:        "enterprise_coaching_dashboard",
+		displayName: "Enterprise Coaching Dashboard",
+		description: "Has access to the Percipio enterprise coaching dashboard",
+		holds:       func(user client.User) bool { return user.HasEnterpriseCoachingDashboard },
+	},
+	{
+		slug:        "instructor",
+		displayName: "Instructor",
+		description: "Is a Percipio instructor",
+		holds:       func(user client.User) bool { return user.IsInstructor },
+	},
+}
+
+// organizationBuilder struct is responsible for syncing the Percipio organization, the root of the resource tree.
+// It is used by the connector to parent every other resource and to expose licenses and privileged capabilities as app-level entitlements.
+// It holds a reference to the API client, the organization resource type descriptor, the shared user directory, and the child resource type IDs.
+// This structure organizes the context needed to keep multi-tenant C1Z files unambiguous.
+// Instances are created by the `newOrganizationBuilder` function.
+type organizationBuilder struct {
+	client       *client.Client
+	resourceType *v2.ResourceType
+	users        *userDirectory
+	childTypeIds []string
+}
+
+// ResourceType method returns the resource type descriptor for the organization.
+// It implements the `ResourceType` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method returns the static `organizationResourceType` object defined for this connector.
+// Which informs the baton-sdk about the type of resource this syncer is responsible for.
+// This implementation returns a pre-defined object.
+func (o *organizationBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// organizationResourceID function returns the resource ID of a Percipio organization.
+// It implements the parent reference used by every child resource of the organization.
+// The function uses the organization ID as the resource ID.
+// Which lets child builders and tests refer to the root without building the full resource.
+// This implementation performs no validation.
+func organizationResourceID(organizationId string) *v2.ResourceId {
+	return &v2.ResourceId{
+		ResourceType: organizationResourceType.Id,
+		Resource:     organizationId,
+	}
+}
+
+// organizationResource function creates the `v2.Resource` representing a Percipio organization.
+// It implements the mapping from the organization to the baton-sdk's app resource model.
+// The function names the resource after the organization, records it in the app trait's profile,
+// and annotates it with every child resource type so the baton-sdk lists them under it.
+// Which makes the organization the root that users, courses and every other resource hang off.
+// This implementation falls back to the organization ID when Percipio did not provide a name.
+func organizationResource(organization client.Organization, childTypeIds []string) (*v2.Resource, error) {
+	displayName := organization.Name
+	if displayName == "" {
+		displayName = organization.Id
+	}
+
+	resourceOpts := make([]resourceSdk.ResourceOption, 0, len(childTypeIds))
+	for _, childTypeId := range childTypeIds {
+		resourceOpts = append(resourceOpts, resourceSdk.WithAnnotation(&v2.ChildResourceType{ResourceTypeId: childTypeId}))
+	}
+
+	return resourceSdk.NewAppResource(
+		displayName,
+		organizationResourceType,
+		organization.Id,
+		[]resourceSdk.AppTraitOption{
+			resourceSdk.WithAppProfile(map[string]interface{}{
+				"organization_id":   organization.Id,
+				"organization_name": organization.Name,
+			}),
+		},
+		resourceOpts...,
+	)
+}
+
+// isOptionalLookupError function reports whether a failed lookup may be ignored.
+// It implements the "where available" rule for enriching the organization from the Percipio API.
+// The function matches API errors saying the endpoint or organization is not found or not permitted.
+// Which keeps tokens without access to organization details able to sync.
+// This implementation still reports bad request, authentication, rate limit and server errors so they are retried or surfaced.
+func isOptionalLookupError(err error) bool {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Kind {
+	case client.ErrorKindNotFound, client.ErrorKindPermission:
+		return true
+	default:
+		return false
+	}
+}
+
+// List method returns the Percipio organization as the single root resource.
+// It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method enriches the configured organization ID with details from the Percipio organization API.
+// Which provides exactly one root per synced tenant, named after the organization when possible.
+// This implementation falls back to the bare organization ID when the details are not available, and lists nothing under a parent.
+func (o *organizationBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	_ *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	if parentResourceID != nil {
+		return nil, "", nil, nil
+	}
+
+	var outputAnnotations annotations.Annotations
+	organization, ratelimitData, err := o.client.GetOrganization(ctx)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		if !isOptionalLookupError(err) {
+			return nil, "", outputAnnotations, err
+		}
+		ctxzap.Extract(ctx).Debug("organization details are not available", zap.Error(err))
+		organization = &client.Organization{}
+	}
+	organization.Id = o.client.OrganizationId()
+
+	resource, err := organizationResource(*organization, o.childTypeIds)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+	return []*v2.Resource{resource}, "", outputAnnotations, nil
+}
+
+// Entitlements method returns the license and capability entitlements of the organization.
+// It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method declares one entitlement per entry of `licenseEntitlements`.
+// Which turns paid seats and the instructor privilege into reviewable access.
+// This implementation returns a static list of assignment entitlements.
+func (o *organizationBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	entitlements := make([]*v2.Entitlement, 0, len(licenseEntitlements))
+	for _, license := range licenseEntitlements {
+		entitlements = append(entitlements, entitlement.NewAssignmentEntitlement(
+			resource,
+			license.slug,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("%s %s", resource.DisplayName, license.displayName)),
+			entitlement.WithDescription(license.description),
+		))
+	}
+	return entitlements, "", nil, nil
+}
+
+// Grants method returns a grant for every license or capability a user holds.
+// It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method checks each user in the shared user directory against every entry of `licenseEntitlements`.
+// Which makes license utilization measurable, including seats still assigned to deactivated users.
+// This implementation reads the shared user directory instead of listing users again.
+func (o *organizationBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	users, outputAnnotations, err := o.users.all(ctx)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, user := range users {
+		for _, license := range licenseEntitlements {
+			if !license.holds(user) {
+				continue
+			}
+			principalId, err := resourceSdk.NewResourceID(userResourceType, user.Id)
+			if err != nil {
+				return nil, "", outputAnnotations, err
+			}
+			grants = append(grants, grant.NewGrant(resource, license.slug, principalId))
+		}
+	}
+
+	return grants, "", outputAnnotations, nil
+}
+
+// newOrganizationBuilder function creates a new `organizationBuilder`.
+// It implements the constructor for the organization resource syncer.
+// The function initializes an `organizationBuilder` with an API client, the organization resource type,
+// the shared user directory, and the IDs of the resource types to list under the organization.
+// Which provides a configured syncer ready to be used by the main connector.
+// This implementation sets up the builder with its required dependencies.
+func newOrganizationBuilder(client *client.Client, users *userDirectory, childTypeIds []string) *organizationBuilder {
+	return &organizationBuilder{
+		client:       client,
+		resourceType: organizationResourceType,
+		users:        users,
+		childTypeIds: childTypeIds,
+	}
+}
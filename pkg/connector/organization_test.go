@@ -0,0 +1,149 @@
+package connector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-percipio/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOrganization(t *testing.T) {
+	ctx := context.Background()
+	server := test.FixturesServer()
+	defer server.Close()
+
+	percipioClient, err := client.New(
+		ctx,
+		server.URL,
+		"mock",
+		"token",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should list the organization as the root", func(t *testing.T) {
+		o := newOrganizationBuilder(percipioClient, newUserDirectory(percipioClient), []string{userResourceType.Id, courseResourceType.Id})
+		resources, nextToken, listAnnotations, err := o.List(ctx, nil, &pagination.Token{})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, listAnnotations)
+		require.Empty(t, nextToken)
+		require.Len(t, resources, 1)
+		require.Equal(t, organizationResourceType.Id, resources[0].Id.ResourceType)
+		require.Equal(t, "mock", resources[0].Id.Resource)
+		require.Equal(t, "Mock Learning Co", resources[0].DisplayName)
+		require.Nil(t, resources[0].ParentResourceId)
+
+		childTypeIds := make([]string, 0)
+		for _, a := range resources[0].Annotations {
+			childType := &v2.ChildResourceType{}
+			if a.MessageIs(childType) {
+				require.Nil(t, a.UnmarshalTo(childType))
+				childTypeIds = append(childTypeIds, childType.ResourceTypeId)
+			}
+		}
+		require.ElementsMatch(t, []string{userResourceType.Id, courseResourceType.Id}, childTypeIds)
+
+		entitlements, _, _, err := o.Entitlements(ctx, resources[0], &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, len(licenseEntitlements))
+
+		resources, _, _, err = o.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		require.Empty(t, resources)
+	})
+
+	t.Run("should fall back to the organization ID when no name is known", func(t *testing.T) {
+		organization, err := organizationResource(client.Organization{Id: "mock"}, nil)
+		require.Nil(t, err)
+		require.Equal(t, "mock", organization.DisplayName)
+	})
+
+	t.Run("should only tolerate organization details that are not available", func(t *testing.T) {
+		for _, testCase := range []struct {
+			statusCode int
+			expected   codes.Code
+		}{
+			{http.StatusNotFound, codes.OK},
+			{http.StatusForbidden, codes.OK},
+			{http.StatusBadRequest, codes.InvalidArgument},
+		} {
+			organizationServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+				writer.WriteHeader(testCase.statusCode)
+			}))
+
+			organizationClient, err := client.New(ctx, organizationServer.URL, "mock", "token")
+			require.Nil(t, err)
+			resources, _, _, err := newOrganizationBuilder(organizationClient, newUserDirectory(organizationClient), nil).List(ctx, nil, &pagination.Token{})
+			require.Equal(t, testCase.expected, status.Code(err), testCase.statusCode)
+			if testCase.expected == codes.OK {
+				require.Len(t, resources, 1)
+				require.Equal(t, "mock", resources[0].DisplayName)
+			}
+
+			organizationServer.Close()
+		}
+	})
+
+	t.Run("should parent every resource type under the organization", func(t *testing.T) {
+		connector, err := New(ctx, "", server.URL, "mock", "token", nil)
+		require.Nil(t, err)
+		syncers := connector.ResourceSyncers(ctx)
+		require.Equal(t, organizationResourceType.Id, syncers[0].ResourceType(ctx).Id)
+
+		for _, syncer := range syncers[1:] {
+			resources, _, _, err := syncer.List(ctx, nil, &pagination.Token{})
+			require.Nil(t, err)
+			require.Empty(t, resources, syncer.ResourceType(ctx).Id)
+		}
+
+		users, _, _, err := syncers[1].List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		require.NotEmpty(t, users)
+		require.Equal(t, organizationResourceID("mock"), users[0].ParentResourceId)
+	})
+
+	t.Run("should grant licenses held by users", func(t *testing.T) {
+		o := newOrganizationBuilder(percipioClient, newUserDirectory(percipioClient), nil)
+		app, err := organizationResource(client.Organization{Id: percipioClient.OrganizationId()}, nil)
+		require.Nil(t, err)
+
+		grants, _, listAnnotations, err := o.Grants(ctx, app, &pagination.Token{})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, listAnnotations)
+
+		granted := make([]string, 0, len(grants))
+		for _, appGrant := range grants {
+			require.Equal(t, "00000000-0000-0000-0000-000000000001", appGrant.Principal.Id.Resource)
+			granted = append(granted, appGrant.Entitlement.Id)
+		}
+		require.ElementsMatch(t, []string{
+			entitlement.NewEntitlementID(app, "coaching"),
+			entitlement.NewEntitlementID(app, "enterprise_coaching_dashboard"),
+		}, granted)
+	})
+
+	t.Run("should record the coaching dashboard flag in the user profile", func(t *testing.T) {
+		user, err := userResource(client.User{
+			Id:                             "00000000-0000-0000-0000-000000000001",
+			HasEnterpriseCoaching:          false,
+			HasEnterpriseCoachingDashboard: true,
+		}, nil)
+		require.Nil(t, err)
+
+		userTrait, err := resourceSdk.GetUserTrait(user)
+		require.Nil(t, err)
+		require.True(t, userTrait.GetProfile().GetFields()["has_enterprise_coaching_dashboard"].GetBoolValue())
+		require.False(t, userTrait.GetProfile().GetFields()["has_enterprise_coaching"].GetBoolValue())
+	})
+}
@@ -2,30 +2,17 @@ package connector
 
 import (
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
-	"github.com/conductorone/baton-sdk/pkg/annotations"
 )
 
-// annotationsForUserResourceType function creates the annotations for the user resource type.
-// It implements the annotation logic required by the user resource type definition.
-// The function creates a `SkipEntitlementsAndGrants` annotation to inform the SDK that users, as principals, do not have their own entitlements or grants.
-// Which is a necessary configuration for principal resource types in the baton-sdk.
-// This implementation returns a new `annotations.Annotations` object with the appropriate annotation added.
-func annotationsForUserResourceType() annotations.Annotations {
-	annos := annotations.Annotations{}
-	annos.Update(&v2.SkipEntitlementsAndGrants{})
-	return annos
-}
-
 // userResourceType is the resource type descriptor for users.
 // It is used by the user resource syncer to define the user resource type.
-// It holds the `Id`, `DisplayName`, `Traits`, and `Annotations` for the user resource type.
-// This variable defines the schema for user resources in Baton.
-// The instance is configured with the `TRAIT_USER` trait and annotations to skip grants and entitlements.
+// It holds the `Id`, `DisplayName`, and `Traits` for the user resource type.
+// This variable defines the schema for user resources in Baton, including the approval-manager relationship between users.
+// The instance is configured with the `TRAIT_USER` trait.
 var userResourceType = &v2.ResourceType{
 	Id:          "user",
 	DisplayName: "User",
 	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_USER},
-	Annotations: annotationsForUserResourceType(),
 }
 
 // courseResourceType is the resource type descriptor for courses.
@@ -37,3 +24,77 @@ var courseResourceType = &v2.ResourceType{
 	Id:          "course",
 	DisplayName: "course",
 }
+
+// assessmentResourceType is the resource type descriptor for assessments.
+// It is used by the assessment resource syncer to define the assessment resource type.
+// It holds the `Id` and `DisplayName` for the assessment resource type.
+// This variable defines the schema for assessment resources in Baton, separate from courses so outcomes can be reviewed.
+// The instance is configured with a simple ID and display name.
+var assessmentResourceType = &v2.ResourceType{
+	Id:          "assessment",
+	DisplayName: "assessment",
+}
+
+// channelResourceType is the resource type descriptor for channels.
+// It is used by the channel resource syncer to define the channel resource type.
+// It holds the `Id` and `DisplayName` for the channel resource type.
+// This variable defines the schema for channel resources in Baton, the curricula that group courses.
+// The instance is configured with a simple ID and display name.
+var channelResourceType = &v2.ResourceType{
+	Id:          "channel",
+	DisplayName: "channel",
+}
+
+// journeyResourceType is the resource type descriptor for journeys.
+// It is used by the journey resource syncer to define the journey resource type.
+// It holds the `Id` and `DisplayName` for the journey resource type.
+// This variable defines the schema for journey resources in Baton, the learning paths that sequence catalog content.
+// The instance is configured with a simple ID and display name.
+var journeyResourceType = &v2.ResourceType{
+	Id:          "journey",
+	DisplayName: "journey",
+}
+
+// roleResourceType is the resource type descriptor for roles.
+// It is used by the role resource syncer to define the role resource type.
+// It holds the `Id`, `DisplayName` and `Traits` for the role resource type.
+// This variable defines the schema for Percipio role resources in Baton, the privilege level assigned to each user.
+// The instance is configured with the `TRAIT_ROLE` trait.
+var roleResourceType = &v2.ResourceType{
+	Id:          "role",
+	DisplayName: "Role",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_ROLE},
+}
+
+// audienceResourceType is the resource type descriptor for audiences.
+// It is used by the audience resource syncer to define the audience resource type.
+// It holds the `Id`, `DisplayName` and `Traits` for the audience resource type.
+// This variable defines the schema for Percipio audience resources in Baton, the user groups content is assigned to.
+// The instance is configured with the `TRAIT_GROUP` trait.
+var audienceResourceType = &v2.ResourceType{
+	Id:          "audience",
+	DisplayName: "Audience",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+}
+
+// attributeGroupResourceType is the resource type descriptor for attribute groups.
+// It is used by the attribute group resource syncer to define the attribute group resource type.
+// It holds the `Id`, `DisplayName` and `Traits` for the attribute group resource type.
+// This variable defines the schema for groups synthesized from user values such as department or cost center.
+// The instance is configured with the `TRAIT_GROUP` trait.
+var attributeGroupResourceType = &v2.ResourceType{
+	Id:          "attribute_group",
+	DisplayName: "Attribute Group",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
+}
+
+// organizationResourceType is the resource type descriptor for the Percipio organization.
+// It is used by the organization resource syncer to define the root of the resource tree.
+// It holds the `Id`, `DisplayName` and `Traits` for the organization resource type.
+// This variable defines the schema for the tenant-level resource that parents every other resource and carries license entitlements.
+// The instance is configured with the `TRAIT_APP` trait.
+var organizationResourceType = &v2.ResourceType{
+	Id:          "organization",
+	DisplayName: "Organization",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_APP},
+}
@@ -0,0 +1,335 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const memberEntitlement = "member"
+
+// roleBuilder struct is responsible for syncing Percipio roles and the users holding them.
+// It is used by the connector to expose privileges such as admin, manager and content curator to access reviews.
+// It holds a reference to the API client, the role resource type descriptor, and the shared user directory.
+// This structure organizes the context needed for all role-related synchronization operations.
+// Instances are created by the `newRoleBuilder` function.
+type roleBuilder struct {
+	client       *client.Client
+	resourceType *v2.ResourceType
+	users        *userDirectory
+}
+
+// ResourceType method returns the resource type descriptor for roles.
+// It implements the `ResourceType` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method returns the static `roleResourceType` object defined for this connector.
+// Which informs the baton-sdk about the type of resource this syncer is responsible for.
+// This implementation returns a pre-defined object.
+func (o *roleBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
+	return o.resourceType
+}
+
+// roleDisplayName function turns a Percipio role code into a human-readable name.
+// It implements the naming of role resources, whose codes are upper snake case (e.g. "CONTENT_CURATOR").
+// The function lowercases the code, replaces underscores with spaces and capitalizes each word.
+// Which makes roles read naturally in access reviews.
+// This implementation returns the code unchanged when it has no letters to capitalize.
+func roleDisplayName(role string) string {
+	words := strings.Fields(strings.ReplaceAll(strings.ToLower(role), "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	if len(words) == 0 {
+		return role
+	}
+	return strings.Join(words, " ")
+}
+
+// roleResource function creates a new `v2.Resource` for a Percipio role.
+// It implements the mapping from a user's role code to the baton-sdk's resource model.
+// The function uses the role code as the resource ID and records it in the role trait's profile.
+// Which gives each distinct role a stable identity that grants and provisioning can refer to.
+// This implementation uses the `resourceSdk.NewRoleResource` helper to construct a resource with the `RoleTrait`.
+func roleResource(role string, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	return resourceSdk.NewRoleResource(
+		roleDisplayName(role),
+		roleResourceType,
+		role,
+		[]resourceSdk.RoleTraitOption{
+			resourceSdk.WithRoleProfile(map[string]interface{}{
+				"role": role,
+			}),
+		},
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+// List method returns every distinct Percipio role as `v2.Resource` objects.
+// It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method collects the roles held by the tenant's users and returns them sorted by code.
+// Which enumerates the roles in use, since Percipio has no endpoint listing them.
+// This implementation returns all roles in a single page, skips users without a role, and only lists roles under the organization.
+func (o *roleBuilder) List(
+	ctx context.Context,
+	parentResourceID *v2.ResourceId,
+	_ *pagination.Token,
+) (
+	[]*v2.Resource,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	users, outputAnnotations, err := o.users.all(ctx)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	roles := make([]string, 0)
+	for _, user := range users {
+		if user.Role != "" && !slices.Contains(roles, user.Role) {
+			roles = append(roles, user.Role)
+		}
+	}
+	slices.Sort(roles)
+
+	outputResources := make([]*v2.Resource, 0, len(roles))
+	for _, role := range roles {
+		resource, err := roleResource(role, parentResourceID)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		outputResources = append(outputResources, resource)
+	}
+
+	return outputResources, "", outputAnnotations, nil
+}
+
+// Entitlements method returns the entitlements for a role resource.
+// It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method defines the 'member' entitlement for a given role.
+// Which allows Baton to review and certify who holds each Percipio privilege.
+// This implementation returns a static list of one assignment entitlement.
+func (o *roleBuilder) Entitlements(
+	_ context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Entitlement,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			memberEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("Role %s %s", resource.DisplayName, memberEntitlement)),
+			entitlement.WithDescription(fmt.Sprintf("Has the %s role in Percipio", resource.DisplayName)),
+		),
+	}, "", nil, nil
+}
+
+// Grants method returns a grant for every user holding a role.
+// It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method matches each user's role code against the role resource ID.
+// Which turns the role stored on every Percipio user into reviewable access.
+// This implementation reads the shared user directory instead of listing users again.
+func (o *roleBuilder) Grants(
+	ctx context.Context,
+	resource *v2.Resource,
+	_ *pagination.Token,
+) (
+	[]*v2.Grant,
+	string,
+	annotations.Annotations,
+	error,
+) {
+	users, outputAnnotations, err := o.users.all(ctx)
+	if err != nil {
+		return nil, "", outputAnnotations, err
+	}
+
+	grants := make([]*v2.Grant, 0)
+	for _, user := range users {
+		if user.Role != resource.Id.Resource {
+			continue
+		}
+		principalId, err := resourceSdk.NewResourceID(userResourceType, user.Id)
+		if err != nil {
+			return nil, "", outputAnnotations, err
+		}
+		grants = append(grants, grant.NewGrant(resource, memberEntitlement, principalId))
+	}
+
+	return grants, "", outputAnnotations, nil
+}
+
+// roleTarget function extracts the role code and user ID from a role provisioning request.
+// It implements the validation shared by the role `Grant` and `Revoke` methods.
+// The function checks that the principal is a user and that the entitlement is the role's 'member' entitlement.
+// Which rejects requests the user-management API cannot express before any call is made.
+// This implementation compares entitlement IDs, since synced grants do not carry the entitlement slug.
+func roleTarget(principal *v2.Resource, roleEntitlement *v2.Entitlement) (string, string, error) {
+	if principal.GetId().GetResourceType() != userResourceType.Id {
+		return "", "", status.Errorf(codes.InvalidArgument, "baton-percipio: only users can be granted roles, got %s", principal.GetId().GetResourceType())
+	}
+	if roleEntitlement.GetId() != entitlement.NewEntitlementID(roleEntitlement.GetResource(), memberEntitlement) {
+		return "", "", status.Errorf(codes.InvalidArgument, "baton-percipio: only the %s entitlement can be provisioned, got %s", memberEntitlement, roleEntitlement.GetId())
+	}
+	return roleEntitlement.GetResource().GetId().GetResource(), principal.GetId().GetResource(), nil
+}
+
+// checkNotLastAdmin function refuses to take the admin role away from the last active admin.
+// It implements the guardrail applied by the role `Grant` and `Revoke` methods before they change an admin's role,
+// and by the `disable_user` action before it deactivates an admin.
+// The method lists every user and counts the active users holding the `ADMIN` role.
+// Which keeps provisioning from locking every administrator out of the Percipio tenant.
+// This implementation lists the users past the HTTP cache, so neither the sync snapshot nor an earlier grant hides a recent demotion,
+// and returns a `FailedPrecondition` error when no other active admin remains.
+func checkNotLastAdmin(ctx context.Context, percipioClient *client.Client, user *client.User) (annotations.Annotations, error) {
+	if user.Role != config.AdminRole {
+		return nil, nil
+	}
+
+	users, outputAnnotations, err := listAllUsers(client.Uncached(ctx), percipioClient)
+	if err != nil {
+		return outputAnnotations, err
+	}
+
+	for _, other := range users {
+		if other.Id != user.Id && other.Role == config.AdminRole && other.IsActive {
+			return outputAnnotations, nil
+		}
+	}
+	return outputAnnotations, status.Errorf(codes.FailedPrecondition, "baton-percipio: user %s is the last admin of the organization", user.Id)
+}
+
+// setRole method changes the role of a Percipio user.
+// It implements the update shared by the role `Grant` and `Revoke` methods.
+// The method applies the last-admin guardrail and then patches the user's role through the user-management API.
+// Which keeps both directions of role provisioning subject to the same checks.
+// This implementation returns the rate limit annotations of the last call it makes.
+func (o *roleBuilder) setRole(ctx context.Context, user *client.User, role string) (annotations.Annotations, error) {
+	outputAnnotations, err := checkNotLastAdmin(ctx, o.client, user)
+	if err != nil {
+		return outputAnnotations, err
+	}
+
+	_, ratelimitData, err := o.client.UpdateUser(ctx, user.Id, client.UserPatch{Role: role})
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
+}
+
+// Grant method gives a Percipio role to a user.
+// It implements the `Grant` method required by the `connectorbuilder.ResourceProvisionerV2` interface.
+// The method sets the user's role to the role resource ID through the user-management API.
+// Which lets access requests promote users to privileges such as manager or content curator.
+// This implementation reads the user's current role past the HTTP cache, replaces the previous role, since Percipio users hold exactly one role,
+// and refuses to demote the organization's last active admin.
+func (o *roleBuilder) Grant(
+	ctx context.Context,
+	principal *v2.Resource,
+	roleEntitlement *v2.Entitlement,
+) (
+	[]*v2.Grant,
+	annotations.Annotations,
+	error,
+) {
+	var outputAnnotations annotations.Annotations
+	role, userId, err := roleTarget(principal, roleEntitlement)
+	if err != nil {
+		return nil, nil, err
+	}
+	grants := []*v2.Grant{
+		grant.NewGrant(roleEntitlement.GetResource(), memberEntitlement, principal.GetId()),
+	}
+
+	user, ratelimitData, err := o.client.GetUser(client.Uncached(ctx), userId)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return nil, outputAnnotations, err
+	}
+	if user.Role == role {
+		outputAnnotations.Update(&v2.GrantAlreadyExists{})
+		return grants, outputAnnotations, nil
+	}
+
+	outputAnnotations, err = o.setRole(ctx, user, role)
+	if err != nil {
+		return nil, outputAnnotations, err
+	}
+	return grants, outputAnnotations, nil
+}
+
+// Revoke method takes a Percipio role away from a user.
+// It implements the `Revoke` method required by the `connectorbuilder.ResourceProvisionerV2` interface.
+// The method moves the user to the role configured with `default-role` through the user-management API.
+// Which removes the privilege without leaving the user role-less, something Percipio does not allow.
+// This implementation reads the user's current role past the HTTP cache, reports the grant as already revoked when the user no longer holds the role,
+// refuses to revoke the default role itself, and refuses to demote the organization's last active admin.
+func (o *roleBuilder) Revoke(
+	ctx context.Context,
+	roleGrant *v2.Grant,
+) (
+	annotations.Annotations,
+	error,
+) {
+	var outputAnnotations annotations.Annotations
+	role, userId, err := roleTarget(roleGrant.GetPrincipal(), roleGrant.GetEntitlement())
+	if err != nil {
+		return nil, err
+	}
+
+	user, ratelimitData, err := o.client.GetUser(client.Uncached(ctx), userId)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return outputAnnotations, err
+	}
+	if user.Role != role {
+		outputAnnotations.Update(&v2.GrantAlreadyRevoked{})
+		return outputAnnotations, nil
+	}
+
+	defaultRole := o.client.DefaultRole()
+	if role == defaultRole {
+		return outputAnnotations, status.Errorf(codes.FailedPrecondition, "baton-percipio: the default role %s cannot be revoked, grant another role instead", role)
+	}
+
+	outputAnnotations, err = o.setRole(ctx, user, defaultRole)
+	if err != nil {
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
+}
+
+// newRoleBuilder function creates a new `roleBuilder`.
+// It implements the constructor for the role resource syncer.
+// The function initializes a `roleBuilder` with an API client, the role resource type, and the shared user directory.
+// Which provides a configured syncer ready to be used by the main connector.
+// This implementation sets up the builder with its required dependencies.
+func newRoleBuilder(client *client.Client, users *userDirectory) *roleBuilder {
+	return &roleBuilder{
+		client:       client,
+		resourceType: roleResourceType,
+		users:        users,
+	}
+}
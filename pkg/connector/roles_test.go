@@ -0,0 +1,253 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-percipio/test"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRoles(t *testing.T) {
+	ctx := context.Background()
+	server := test.FixturesServer()
+	defer server.Close()
+
+	percipioClient, err := client.New(
+		ctx,
+		server.URL,
+		"mock",
+		"token",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should list distinct roles", func(t *testing.T) {
+		r := newRoleBuilder(percipioClient, newUserDirectory(percipioClient))
+		resources, nextToken, listAnnotations, err := r.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.Nil(t, err)
+		test.AssertNoRatelimitAnnotations(t, listAnnotations)
+		require.Empty(t, nextToken)
+		require.Len(t, resources, 1)
+		require.Equal(t, roleResourceType.Id, resources[0].Id.ResourceType)
+		require.Equal(t, "LEARNER", resources[0].Id.Resource)
+		require.Equal(t, "Learner", resources[0].DisplayName)
+	})
+
+	t.Run("should grant members their role", func(t *testing.T) {
+		r := newRoleBuilder(percipioClient, newUserDirectory(percipioClient))
+		role, err := roleResource("LEARNER", organizationResourceID("mock"))
+		require.Nil(t, err)
+
+		grants, _, _, err := r.Grants(ctx, role, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+		require.Equal(t, "00000000-0000-0000-0000-000000000001", grants[0].Principal.Id.Resource)
+		require.Equal(t, entitlement.NewEntitlementID(role, memberEntitlement), grants[0].Entitlement.Id)
+
+		admin, err := roleResource("ADMIN", organizationResourceID("mock"))
+		require.Nil(t, err)
+		grants, _, _, err = r.Grants(ctx, admin, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 0)
+	})
+
+	t.Run("should name roles from their codes", func(t *testing.T) {
+		require.Equal(t, "Content Curator", roleDisplayName("CONTENT_CURATOR"))
+		require.Equal(t, "Admin", roleDisplayName("ADMIN"))
+		require.Equal(t, "", roleDisplayName(""))
+	})
+
+	t.Run("should declare the member entitlement", func(t *testing.T) {
+		r := newRoleBuilder(percipioClient, newUserDirectory(percipioClient))
+		entitlements, _, _, err := r.Entitlements(ctx, &v2.Resource{
+			Id:          &v2.ResourceId{ResourceType: roleResourceType.Id, Resource: "ADMIN"},
+			DisplayName: "Admin",
+		}, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 1)
+	})
+}
+
+func TestRoleProvisioning(t *testing.T) {
+	ctx := context.Background()
+
+	newUsersServer := func(users []*client.User, requests *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			*requests = append(*requests, request.Method+" "+request.URL.Path)
+			writer.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(request.URL.Path, "/users") {
+				writer.Header().Set(client.HeaderNameTotalCount, strconv.Itoa(len(users)))
+				_ = json.NewEncoder(writer).Encode(users)
+				return
+			}
+			for _, user := range users {
+				if !strings.HasSuffix(request.URL.Path, "/users/"+user.Id) {
+					continue
+				}
+				if request.Method == http.MethodPatch {
+					var patch client.UserPatch
+					_ = json.NewDecoder(request.Body).Decode(&patch)
+					user.Role = patch.Role
+				}
+				_ = json.NewEncoder(writer).Encode(user)
+				return
+			}
+			writer.WriteHeader(http.StatusNotFound)
+		}))
+	}
+
+	newRoleEntitlement := func(t *testing.T, role string) *v2.Entitlement {
+		resource, err := roleResource(role, organizationResourceID("mock"))
+		require.Nil(t, err)
+		return entitlement.NewAssignmentEntitlement(resource, memberEntitlement)
+	}
+
+	newRoleGrant := func(t *testing.T, role string, principal *v2.Resource) *v2.Grant {
+		resource, err := roleResource(role, organizationResourceID("mock"))
+		require.Nil(t, err)
+		return grant.NewGrant(resource, memberEntitlement, principal.Id)
+	}
+
+	newPrincipal := func(t *testing.T, user *client.User) *v2.Resource {
+		principal, err := userResource(*user, nil)
+		require.Nil(t, err)
+		return principal
+	}
+
+	newBuilder := func(t *testing.T, serverUrl string, options ...client.Option) *roleBuilder {
+		percipioClient, err := client.New(ctx, serverUrl, "mock", "token", options...)
+		require.Nil(t, err)
+		return newRoleBuilder(percipioClient, newUserDirectory(percipioClient))
+	}
+
+	t.Run("should grant a role", func(t *testing.T) {
+		var requests []string
+		user := &client.User{Id: "00000000-0000-0000-0000-000000000001", Role: "LEARNER", IsActive: true}
+		server := newUsersServer([]*client.User{user}, &requests)
+		defer server.Close()
+
+		grants, grantAnnotations, err := newBuilder(t, server.URL).Grant(ctx, newPrincipal(t, user), newRoleEntitlement(t, "MANAGER"))
+		require.Nil(t, err)
+		require.False(t, grantAnnotations.Contains(&v2.GrantAlreadyExists{}))
+		require.Len(t, grants, 1)
+		require.Equal(t, "MANAGER", user.Role)
+		require.Equal(t, []string{
+			"GET /user-management/v1/organizations/mock/users/00000000-0000-0000-0000-000000000001",
+			"PATCH /user-management/v1/organizations/mock/users/00000000-0000-0000-0000-000000000001",
+		}, requests)
+	})
+
+	t.Run("should not update a user already holding the role", func(t *testing.T) {
+		var requests []string
+		user := &client.User{Id: "00000000-0000-0000-0000-000000000001", Role: "MANAGER", IsActive: true}
+		server := newUsersServer([]*client.User{user}, &requests)
+		defer server.Close()
+
+		_, grantAnnotations, err := newBuilder(t, server.URL).Grant(ctx, newPrincipal(t, user), newRoleEntitlement(t, "MANAGER"))
+		require.Nil(t, err)
+		require.True(t, grantAnnotations.Contains(&v2.GrantAlreadyExists{}))
+		require.Len(t, requests, 1)
+	})
+
+	t.Run("should revoke a role to the default role", func(t *testing.T) {
+		var requests []string
+		user := &client.User{Id: "00000000-0000-0000-0000-000000000001", Role: "MANAGER", IsActive: true}
+		server := newUsersServer([]*client.User{user}, &requests)
+		defer server.Close()
+
+		revokeAnnotations, err := newBuilder(t, server.URL, client.WithDefaultRole("CONTENT_CURATOR")).Revoke(ctx, newRoleGrant(t, "MANAGER", newPrincipal(t, user)))
+		require.Nil(t, err)
+		require.False(t, revokeAnnotations.Contains(&v2.GrantAlreadyRevoked{}))
+		require.Equal(t, "CONTENT_CURATOR", user.Role)
+
+		revokeAnnotations, err = newBuilder(t, server.URL).Revoke(ctx, newRoleGrant(t, "MANAGER", newPrincipal(t, user)))
+		require.Nil(t, err)
+		require.True(t, revokeAnnotations.Contains(&v2.GrantAlreadyRevoked{}))
+	})
+
+	t.Run("should refuse to revoke the default role", func(t *testing.T) {
+		var requests []string
+		user := &client.User{Id: "00000000-0000-0000-0000-000000000001", Role: "LEARNER", IsActive: true}
+		server := newUsersServer([]*client.User{user}, &requests)
+		defer server.Close()
+
+		_, err := newBuilder(t, server.URL).Revoke(ctx, newRoleGrant(t, "LEARNER", newPrincipal(t, user)))
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.Equal(t, "LEARNER", user.Role)
+	})
+
+	t.Run("should refuse to demote the last active admin", func(t *testing.T) {
+		var requests []string
+		admin := &client.User{Id: "00000000-0000-0000-0000-000000000001", Role: "ADMIN", IsActive: true}
+		inactiveAdmin := &client.User{Id: "00000000-0000-0000-0000-000000000002", Role: "ADMIN", IsActive: false}
+		server := newUsersServer([]*client.User{admin, inactiveAdmin}, &requests)
+		defer server.Close()
+
+		_, err := newBuilder(t, server.URL).Revoke(ctx, newRoleGrant(t, "ADMIN", newPrincipal(t, admin)))
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+		_, _, err = newBuilder(t, server.URL).Grant(ctx, newPrincipal(t, admin), newRoleEntitlement(t, "MANAGER"))
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.Equal(t, "ADMIN", admin.Role)
+		require.NotContains(t, requests, "PATCH /user-management/v1/organizations/mock/users/00000000-0000-0000-0000-000000000001")
+	})
+
+	t.Run("should demote an admin when another active admin remains", func(t *testing.T) {
+		var requests []string
+		admin := &client.User{Id: "00000000-0000-0000-0000-000000000001", Role: "ADMIN", IsActive: true}
+		otherAdmin := &client.User{Id: "00000000-0000-0000-0000-000000000002", Role: "ADMIN", IsActive: true}
+		server := newUsersServer([]*client.User{admin, otherAdmin}, &requests)
+		defer server.Close()
+
+		_, err := newBuilder(t, server.URL).Revoke(ctx, newRoleGrant(t, "ADMIN", newPrincipal(t, admin)))
+		require.Nil(t, err)
+		require.Equal(t, "LEARNER", admin.Role)
+		require.Equal(t, "ADMIN", otherAdmin.Role)
+	})
+
+	t.Run("should refuse to demote the second of two admins demoted in sequence", func(t *testing.T) {
+		var requests []string
+		first := &client.User{Id: "00000000-0000-0000-0000-000000000001", Role: "ADMIN", IsActive: true}
+		second := &client.User{Id: "00000000-0000-0000-0000-000000000002", Role: "ADMIN", IsActive: true}
+		server := newUsersServer([]*client.User{first, second}, &requests)
+		defer server.Close()
+
+		builder := newBuilder(t, server.URL)
+		admin, err := roleResource("ADMIN", organizationResourceID("mock"))
+		require.Nil(t, err)
+		grants, _, _, err := builder.Grants(ctx, admin, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 2)
+
+		_, err = builder.Revoke(ctx, newRoleGrant(t, "ADMIN", newPrincipal(t, first)))
+		require.Nil(t, err)
+		require.Equal(t, "LEARNER", first.Role)
+
+		_, err = builder.Revoke(ctx, newRoleGrant(t, "ADMIN", newPrincipal(t, second)))
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.Equal(t, "ADMIN", second.Role)
+	})
+
+	t.Run("should only provision the member entitlement to users", func(t *testing.T) {
+		builder := newBuilder(t, "http://127.0.0.1:0")
+		role, err := roleResource("MANAGER", organizationResourceID("mock"))
+		require.Nil(t, err)
+
+		_, _, err = builder.Grant(ctx, role, newRoleEntitlement(t, "MANAGER"))
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -0,0 +1,231 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/conductorone/baton-percipio/pkg/config"
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	accountFieldFirstName        = "first_name"
+	accountFieldLastName         = "last_name"
+	accountFieldEmail            = "email"
+	accountFieldLoginName        = "login_name"
+	accountFieldRole             = "role"
+	accountFieldExternalId       = "external_id"
+	accountFieldCustomAttributes = "custom_attributes"
+)
+
+// accountCreationSchema function describes the fields accepted when creating a Percipio user.
+// It implements the account creation schema published by `Connector.Metadata`.
+// The function declares the required name and email fields, and the optional login name, role, external ID and custom attributes.
+// Which lets the Baton application render an onboarding form that matches what `CreateAccount` reads.
+// This implementation keys every field by the same profile name `userResource` uses when syncing users.
+func accountCreationSchema() *v2.ConnectorAccountCreationSchema {
+	return &v2.ConnectorAccountCreationSchema{
+		FieldMap: map[string]*v2.ConnectorAccountCreationSchema_Field{
+			accountFieldFirstName: {
+				DisplayName: "First name",
+				Required:    true,
+				Description: "The learner's first name.",
+				Placeholder: "Jane",
+				Order:       1,
+				Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+					StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+				},
+			},
+			accountFieldLastName: {
+				DisplayName: "Last name",
+				Required:    true,
+				Description: "The learner's last name.",
+				Placeholder: "Doe",
+				Order:       2,
+				Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+					StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+				},
+			},
+			accountFieldEmail: {
+				DisplayName: "Email",
+				Required:    true,
+				Description: "The learner's email address.",
+				Placeholder: "jane.doe@example.com",
+				Order:       3,
+				Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+					StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+				},
+			},
+			accountFieldLoginName: {
+				DisplayName: "Login name",
+				Description: "The name the learner signs in with. Defaults to the email address.",
+				Placeholder: "jane.doe@example.com",
+				Order:       4,
+				Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+					StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+				},
+			},
+			accountFieldRole: {
+				DisplayName: "Role",
+				Description: "The Percipio role code, such as LEARNER, MANAGER or ADMIN. Defaults to the configured default role.",
+				Placeholder: config.DefaultRoleDefault,
+				Order:       5,
+				Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+					StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+				},
+			},
+			accountFieldExternalId: {
+				DisplayName: "External ID",
+				Description: "The learner's ID in the HR system or identity provider.",
+				Order:       6,
+				Field: &v2.ConnectorAccountCreationSchema_Field_StringField{
+					StringField: &v2.ConnectorAccountCreationSchema_StringField{},
+				},
+			},
+			accountFieldCustomAttributes: {
+				DisplayName: "Custom attributes",
+				Description: "Values of Percipio custom attributes, keyed by custom attribute ID.",
+				Order:       7,
+				Field: &v2.ConnectorAccountCreationSchema_Field_MapField{
+					MapField: &v2.ConnectorAccountCreationSchema_MapField{},
+				},
+			},
+		},
+	}
+}
+
+// profileString function reads a string value from an account creation profile.
+// It implements the lenient field lookup used by `CreateAccount`.
+// The function returns the value stored under `key` when it is a string.
+// Which lets optional fields be omitted without special-casing each of them.
+// This implementation returns an empty string for missing or non-string values.
+func profileString(profile map[string]interface{}, key string) string {
+	value, ok := profile[key].(string)
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+// userRequestFromAccountInfo function converts an account creation request into a Percipio user request.
+// It implements the mapping from the baton-sdk's `AccountInfo` to the user-management API.
+// The function reads the schema fields from the profile, falling back to the account's login and first email address,
+// and defaults the login name to the email address and the role to `defaultRole`.
+// Which keeps `CreateAccount` itself focused on calling the API.
+// This implementation returns an `InvalidArgument` error when a required field is missing.
+func userRequestFromAccountInfo(accountInfo *v2.AccountInfo, defaultRole string) (client.UserRequest, error) {
+	profile := accountInfo.GetProfile().AsMap()
+
+	email := profileString(profile, accountFieldEmail)
+	if email == "" && len(accountInfo.GetEmails()) > 0 {
+		email = accountInfo.GetEmails()[0].GetAddress()
+	}
+	loginName := profileString(profile, accountFieldLoginName)
+	if loginName == "" {
+		loginName = accountInfo.GetLogin()
+	}
+	if loginName == "" {
+		loginName = email
+	}
+	role := profileString(profile, accountFieldRole)
+	if role == "" {
+		role = defaultRole
+	}
+
+	user := client.UserRequest{
+		FirstName:      profileString(profile, accountFieldFirstName),
+		LastName:       profileString(profile, accountFieldLastName),
+		Email:          email,
+		LoginName:      loginName,
+		Role:           role,
+		ExternalUserId: profileString(profile, accountFieldExternalId),
+		IsActive:       true,
+	}
+
+	for field, value := range map[string]string{
+		accountFieldFirstName: user.FirstName,
+		accountFieldLastName:  user.LastName,
+		accountFieldEmail:     user.Email,
+	} {
+		if value == "" {
+			return client.UserRequest{}, status.Errorf(codes.InvalidArgument, "baton-percipio: %s is required to create a user", field)
+		}
+	}
+
+	customAttributes, _ := profile[accountFieldCustomAttributes].(map[string]interface{})
+	for id, value := range customAttributes {
+		user.CustomAttributes = append(user.CustomAttributes, client.CustomAttributeValue{
+			Id:    id,
+			Value: fmt.Sprint(value),
+		})
+	}
+	sort.Slice(user.CustomAttributes, func(i, j int) bool {
+		return user.CustomAttributes[i].Id < user.CustomAttributes[j].Id
+	})
+
+	return user, nil
+}
+
+// CreateAccount method creates a Percipio user from an account creation request.
+// It implements the `CreateAccount` method required by the `connectorbuilder.AccountManager` interface.
+// The method posts the user's details to the user-management API and returns the created user as a resource.
+// Which lets onboarding create learners without a Percipio admin.
+// This implementation issues no credentials, since learners sign in through Percipio or SSO, and places the user under the organization.
+func (o *userBuilder) CreateAccount(
+	ctx context.Context,
+	accountInfo *v2.AccountInfo,
+	_ *v2.CredentialOptions,
+) (
+	connectorbuilder.CreateAccountResponse,
+	[]*v2.PlaintextData,
+	annotations.Annotations,
+	error,
+) {
+	var outputAnnotations annotations.Annotations
+	request, err := userRequestFromAccountInfo(accountInfo, o.client.DefaultRole())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	user, ratelimitData, err := o.client.CreateUser(ctx, request)
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if err != nil {
+		return nil, nil, outputAnnotations, err
+	}
+
+	resource, err := userResource(*user, organizationResourceID(o.client.OrganizationId()))
+	if err != nil {
+		return nil, nil, outputAnnotations, err
+	}
+
+	return &v2.CreateAccountResponse_SuccessResult{
+		Resource: resource,
+	}, nil, outputAnnotations, nil
+}
+
+// CreateAccountCapabilityDetails method describes the credentials supported when creating accounts.
+// It implements the `CreateAccountCapabilityDetails` method required by the `connectorbuilder.AccountManager` interface.
+// The method declares that accounts are created without a password, or for single sign-on.
+// Which tells the Baton application not to generate or expect a password for new learners.
+// This implementation returns a static description.
+func (o *userBuilder) CreateAccountCapabilityDetails(
+	_ context.Context,
+) (
+	*v2.CredentialDetailsAccountProvisioning,
+	annotations.Annotations,
+	error,
+) {
+	return &v2.CredentialDetailsAccountProvisioning{
+		SupportedCredentialOptions: []v2.CapabilityDetailCredentialOption{
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+			v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_SSO,
+		},
+		PreferredCredentialOption: v2.CapabilityDetailCredentialOption_CAPABILITY_DETAIL_CREDENTIAL_OPTION_NO_PASSWORD,
+	}, nil, nil
+}
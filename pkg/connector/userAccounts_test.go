@@ -0,0 +1,109 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestUserAccounts(t *testing.T) {
+	ctx := context.Background()
+
+	newProfile := func(t *testing.T, fields map[string]interface{}) *structpb.Struct {
+		profile, err := structpb.NewStruct(fields)
+		require.Nil(t, err)
+		return profile
+	}
+
+	t.Run("should create a user", func(t *testing.T) {
+		var requested client.UserRequest
+		var path string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			path = request.Method + " " + request.URL.Path
+			_ = json.NewDecoder(request.Body).Decode(&requested)
+			writer.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(writer).Encode(client.User{
+				Id:        "00000000-0000-0000-0000-000000000003",
+				FirstName: requested.FirstName,
+				LastName:  requested.LastName,
+				Email:     requested.Email,
+				LoginName: requested.LoginName,
+				Role:      requested.Role,
+				IsActive:  true,
+			})
+		}))
+		defer server.Close()
+
+		percipioClient, err := client.New(ctx, server.URL, "mock", "token")
+		require.Nil(t, err)
+
+		response, plaintexts, _, err := newUserBuilder(percipioClient).CreateAccount(ctx, &v2.AccountInfo{
+			Profile: newProfile(t, map[string]interface{}{
+				"first_name":  "Jane",
+				"last_name":   "Doe",
+				"email":       "jane.doe@example.com",
+				"external_id": "E-1001",
+				"custom_attributes": map[string]interface{}{
+					"291c7401-8951-4177-942d-da2fc7e9da1a": "Engineering",
+				},
+			}),
+		}, nil)
+		require.Nil(t, err)
+		require.Empty(t, plaintexts)
+		require.Equal(t, "POST /user-management/v1/organizations/mock/users", path)
+		require.Equal(t, client.UserRequest{
+			FirstName:      "Jane",
+			LastName:       "Doe",
+			Email:          "jane.doe@example.com",
+			LoginName:      "jane.doe@example.com",
+			Role:           "LEARNER",
+			ExternalUserId: "E-1001",
+			IsActive:       true,
+			CustomAttributes: []client.CustomAttributeValue{
+				{Id: "291c7401-8951-4177-942d-da2fc7e9da1a", Value: "Engineering"},
+			},
+		}, requested)
+
+		success, ok := response.(*v2.CreateAccountResponse_SuccessResult)
+		require.True(t, ok)
+		require.Equal(t, "00000000-0000-0000-0000-000000000003", success.Resource.Id.Resource)
+		require.Equal(t, "Jane Doe", success.Resource.DisplayName)
+		require.Equal(t, organizationResourceID("mock"), success.Resource.ParentResourceId)
+	})
+
+	t.Run("should require a name and email", func(t *testing.T) {
+		percipioClient, err := client.New(ctx, "http://127.0.0.1:0", "mock", "token")
+		require.Nil(t, err)
+
+		_, _, _, err = newUserBuilder(percipioClient).CreateAccount(ctx, &v2.AccountInfo{
+			Profile: newProfile(t, map[string]interface{}{
+				"first_name": "Jane",
+				"email":      "jane.doe@example.com",
+			}),
+		}, nil)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("should publish the account creation schema", func(t *testing.T) {
+		metadata, err := (&Connector{}).Metadata(ctx)
+		require.Nil(t, err)
+
+		required := make([]string, 0)
+		for name, field := range metadata.GetAccountCreationSchema().GetFieldMap() {
+			if field.GetRequired() {
+				required = append(required, name)
+			}
+		}
+		require.ElementsMatch(t, []string{"first_name", "last_name", "email"}, required)
+		require.Contains(t, metadata.GetAccountCreationSchema().GetFieldMap(), "custom_attributes")
+	})
+}
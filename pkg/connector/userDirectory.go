@@ -0,0 +1,105 @@
+package connector
+
+import (
+	"context"
+	"sync"
+
+	"github.com/conductorone/baton-percipio/pkg/connector/client"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// userDirectory struct is an in-memory snapshot of every Percipio user.
+// It is used by the builders whose resources and grants are derived from user attributes, such as roles.
+// It holds a reference to the API client and, once loaded, the deduplicated list of users.
+// This structure organizes a single full user listing shared by several builders instead of one listing per resource.
+// Instances are created by the `newUserDirectory` function, once per call to `ResourceSyncers`, and emptied after every sync by `reset`.
+type userDirectory struct {
+	client *client.Client
+
+	mu     sync.Mutex
+	users  []client.User
+	loaded bool
+}
+
+// newUserDirectory function creates a new `userDirectory`.
+// It implements the constructor for the shared user snapshot.
+// The function initializes the directory with an API client.
+// Which lets the connector hand the same directory to every builder that needs it.
+// This implementation does not call the API until the users are first needed.
+func newUserDirectory(client *client.Client) *userDirectory {
+	return &userDirectory{
+		client: client,
+	}
+}
+
+// all method returns every user of the Percipio tenant.
+// It implements the lookup shared by the user-derived resource builders.
+// The method lists the users with `listAllUsers` on first use and caches the result until the next `reset`.
+// Which keeps the number of user listings constant regardless of how many derived resources are synced.
+// This implementation only caches a complete listing, so a failed listing is retried on the next call.
+func (d *userDirectory) all(ctx context.Context) ([]client.User, annotations.Annotations, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.loaded {
+		return d.users, annotations.Annotations{}, nil
+	}
+
+	users, outputAnnotations, err := listAllUsers(ctx, d.client)
+	if err != nil {
+		return nil, outputAnnotations, err
+	}
+
+	ctxzap.Extract(ctx).Debug("loaded user directory", zap.Int("users", len(users)))
+	d.users = users
+	d.loaded = true
+	return d.users, outputAnnotations, nil
+}
+
+// reset method drops the cached users.
+// It implements the `syncCache` interface used by the connector's `Cleanup`.
+// The method clears the snapshot so the next call to `all` lists the users again.
+// Which keeps a long-running connector from serving the first sync's users to every later sync.
+// This implementation keeps the client.
+func (d *userDirectory) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.users = nil
+	d.loaded = false
+}
+
+// listAllUsers function returns every user of the Percipio tenant.
+// It implements the full user listing behind the `userDirectory` cache and provisioning checks that must not read a stale snapshot.
+// The function pages through the user management API until every user has been seen.
+// Which gives callers a complete view of the tenant in one call.
+// This implementation drops users returned more than once and stops paging when a page comes back empty.
+func listAllUsers(ctx context.Context, percipioClient *client.Client) ([]client.User, annotations.Annotations, error) {
+	var outputAnnotations annotations.Annotations
+	seen := mapset.NewThreadUnsafeSet[string]()
+	users := make([]client.User, 0)
+	offset, limit := 0, client.PageSizeDefault
+	for {
+		page, total, ratelimitData, err := percipioClient.GetUsers(ctx, offset, limit)
+		outputAnnotations = annotations.Annotations{}
+		outputAnnotations.WithRateLimiting(ratelimitData)
+		if err != nil {
+			return nil, outputAnnotations, err
+		}
+
+		for _, user := range page {
+			if seen.Add(user.Id) {
+				users = append(users, user)
+			}
+		}
+
+		offset += limit
+		if len(page) == 0 || offset >= total {
+			break
+		}
+	}
+	return users, outputAnnotations, nil
+}
@@ -2,19 +2,27 @@ package connector
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/conductorone/baton-percipio/pkg/connector/client"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	userFullNameDefault = "<no name>"
+	managerEntitlement  = "manager"
 )
 
 // userBuilder struct is responsible for syncing user resources.
@@ -71,11 +79,13 @@ func userResource(user client.User, parentResourceID *v2.ResourceId) (*v2.Resour
 		"id":                                user.Id,
 		"external_id":                       user.ExternalUserId,
 		"approval_manager":                  user.ApprovalManager.Email,
+		"approval_manager_id":               user.ApprovalManager.Id,
+		"approval_manager_external_id":      user.ApprovalManager.ExternalUserId,
 		"email":                             user.Email,
 		"first_name":                        user.FirstName,
 		"has_coaching":                      user.HasCoaching,
 		"has_enterprise_coaching":           user.HasEnterpriseCoaching,
-		"has_enterprise_coaching_dashboard": user.HasEnterpriseCoaching,
+		"has_enterprise_coaching_dashboard": user.HasEnterpriseCoachingDashboard,
 		"is_active":                         user.IsActive,
 		"is_instructor":                     user.IsInstructor,
 		"job_title":                         user.JobTitle,
@@ -118,7 +128,8 @@ func userResource(user client.User, parentResourceID *v2.ResourceId) (*v2.Resour
 // It implements the `List` method required by the `connectorbuilder.ResourceSyncer` interface.
 // The method calls the Percipio API to get a page of users, transforms each user into a resource, and returns the list along with a pagination token.
 // Which enables the baton-sdk to paginate through all user resources in the upstream system.
-// This implementation uses the `client.ParseUserPaginationToken` and `client.GetUserNextToken` functions to handle pagination logic.
+// This implementation uses the `client.ParseUserPaginationToken` and `client.GetUserNextToken` functions to handle pagination logic,
+// and only lists users under the organization.
 func (o *userBuilder) List(
 	ctx context.Context,
 	parentResourceID *v2.ResourceId,
@@ -129,6 +140,10 @@ func (o *userBuilder) List(
 	annotations.Annotations,
 	error,
 ) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
 	logger := ctxzap.Extract(ctx)
 	logger.Debug("Starting Users List", zap.String("token", pToken.Token))
 
@@ -158,14 +173,14 @@ func (o *userBuilder) List(
 	return outputResources, nextToken, outputAnnotations, nil
 }
 
-// Entitlements method is a placeholder as users do not have entitlements themselves.
+// Entitlements method returns the entitlements for a user resource.
 // It implements the `Entitlements` method required by the `connectorbuilder.ResourceSyncer` interface.
-// The method returns an empty slice because users are principals, not resources with entitlements.
-// Which is a required part of the `ResourceSyncer` interface.
-// This implementation correctly returns no entitlements for user resources.
+// The method defines the 'manager' entitlement, which represents managing the given user.
+// Which models Percipio's approval-manager hierarchy as a relationship between user resources.
+// This implementation returns a static list of one assignment entitlement grantable to users.
 func (o *userBuilder) Entitlements(
 	_ context.Context,
-	_ *v2.Resource,
+	resource *v2.Resource,
 	_ *pagination.Token,
 ) (
 	[]*v2.Entitlement,
@@ -173,17 +188,25 @@ func (o *userBuilder) Entitlements(
 	annotations.Annotations,
 	error,
 ) {
-	return nil, "", nil, nil
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			managerEntitlement,
+			entitlement.WithGrantableTo(userResourceType),
+			entitlement.WithDisplayName(fmt.Sprintf("%s manager", resource.DisplayName)),
+			entitlement.WithDescription(fmt.Sprintf("Manages %s as their approval manager in Percipio", resource.DisplayName)),
+		),
+	}, "", nil, nil
 }
 
-// Grants method is a placeholder as users do not have grants to other principals.
-// It implements the `Grants` method required by the `connectorbuilder.Dedupe` interface.
-// The method returns an empty slice because user resources do not grant access in this model.
-// Which is a required part of the `ResourceSyncer` interface.
-// This implementation correctly returns no grants for user resources.
+// Grants method returns the approval-manager grant of a user resource.
+// It implements the `Grants` method required by the `connectorbuilder.ResourceSyncer` interface.
+// The method reads the manager's Percipio user ID from the user trait profile and grants them the 'manager' entitlement.
+// Which lets access reviews route approvals to the right manager and spot users whose manager has been deactivated.
+// This implementation returns no grants for users without an approval manager, and never grants a user management of themselves.
 func (o *userBuilder) Grants(
 	_ context.Context,
-	_ *v2.Resource,
+	resource *v2.Resource,
 	_ *pagination.Token,
 ) (
 	[]*v2.Grant,
@@ -191,7 +214,79 @@ func (o *userBuilder) Grants(
 	annotations.Annotations,
 	error,
 ) {
-	return nil, "", nil, nil
+	userTrait, err := resourceSdk.GetUserTrait(resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	managerId, ok := resourceSdk.GetProfileStringValue(userTrait.GetProfile(), "approval_manager_id")
+	if !ok || managerId == "" || managerId == resource.Id.Resource {
+		return nil, "", nil, nil
+	}
+
+	principalId, err := resourceSdk.NewResourceID(userResourceType, managerId)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return []*v2.Grant{
+		grant.NewGrant(resource, managerEntitlement, principalId),
+	}, "", nil, nil
+}
+
+// isDeletionRefusedError function reports whether Percipio refused to delete a user.
+// It implements the classification of the errors returned by `client.DeleteUser`.
+// The function matches API errors with a 409 Conflict status.
+// Which is how Percipio answers a deletion of a user with recorded learning activity.
+// This implementation leaves every other error, including other 4xx replies such as a malformed ID, to the `APIError` gRPC mapping.
+func isDeletionRefusedError(err error) bool {
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusConflict
+}
+
+// Delete method deletes a Percipio user.
+// It implements the `Delete` method required by the `connectorbuilder.ResourceDeleter` interface.
+// The method removes the user through the user-management API when `allow-user-deletion` is set.
+// Which lets offboarding remove users instead of only disabling them, for tenants that opt in.
+// This implementation treats a user that no longer exists as deleted,
+// and returns a `FailedPrecondition` error when deletion is disabled or Percipio refuses it because the user has recorded activity.
+// Percipio has no endpoint to anonymize a user, so users that cannot be deleted can only be disabled.
+func (o *userBuilder) Delete(
+	ctx context.Context,
+	resourceId *v2.ResourceId,
+) (
+	annotations.Annotations,
+	error,
+) {
+	if resourceId.GetResourceType() != userResourceType.Id {
+		return nil, status.Errorf(codes.InvalidArgument, "baton-percipio: only users can be deleted, got %s", resourceId.GetResourceType())
+	}
+	if !o.client.UserDeletionEnabled() {
+		return nil, status.Errorf(codes.FailedPrecondition, "baton-percipio: user deletion is disabled, set allow-user-deletion to enable it")
+	}
+
+	var outputAnnotations annotations.Annotations
+	ratelimitData, err := o.client.DeleteUser(ctx, resourceId.GetResource())
+	outputAnnotations.WithRateLimiting(ratelimitData)
+	if status.Code(err) == codes.NotFound {
+		ctxzap.Extract(ctx).Debug("user already deleted", zap.String("user_id", resourceId.GetResource()))
+		return outputAnnotations, nil
+	}
+	if isDeletionRefusedError(err) {
+		return outputAnnotations, status.Errorf(
+			codes.FailedPrecondition,
+			"baton-percipio: Percipio refused to delete user %s, users with recorded learning activity can only be disabled: %s",
+			resourceId.GetResource(),
+			err.Error(),
+		)
+	}
+	if err != nil {
+		return outputAnnotations, err
+	}
+	return outputAnnotations, nil
 }
 
 // newUserBuilder function creates a new `userBuilder`.
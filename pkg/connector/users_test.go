@@ -2,13 +2,21 @@ package connector
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"github.com/conductorone/baton-percipio/pkg/connector/client"
 	"github.com/conductorone/baton-percipio/test"
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestUsersList(t *testing.T) {
@@ -36,7 +44,7 @@ func TestUsersList(t *testing.T) {
 			Size:  1,
 		}
 		for {
-			nextResources, nextToken, listAnnotations, err := c.List(ctx, nil, &pToken)
+			nextResources, nextToken, listAnnotations, err := c.List(ctx, organizationResourceID("mock"), &pToken)
 			resources = append(resources, nextResources...)
 
 			require.Nil(t, err)
@@ -52,4 +60,191 @@ func TestUsersList(t *testing.T) {
 		require.Len(t, resources, 2)
 		require.NotEmpty(t, resources[0].Id)
 	})
+
+	t.Run("should surface percipio errors as typed api errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+			writer.Header().Set("Content-Type", "application/json")
+			writer.Header().Set("x-request-id", "request-1")
+			writer.WriteHeader(http.StatusUnauthorized)
+			_, _ = writer.Write([]byte(`{"errorCode":"INVALID_TOKEN","message":"Token has been revoked"}`))
+		}))
+		defer server.Close()
+
+		percipioClient, err := client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+		)
+		require.Nil(t, err)
+
+		c := newUserBuilder(percipioClient)
+		_, _, _, err = c.List(ctx, organizationResourceID("mock"), &pagination.Token{})
+		require.NotNil(t, err)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		var apiErr *client.APIError
+		require.True(t, errors.As(err, &apiErr))
+		require.Equal(t, client.ErrorKindAuthentication, apiErr.Kind)
+		require.Equal(t, "INVALID_TOKEN", apiErr.Code)
+		require.Equal(t, "Token has been revoked", apiErr.Message)
+		require.Equal(t, "request-1", apiErr.RequestId)
+	})
+
+	t.Run("should retry throttled requests after retry-after", func(t *testing.T) {
+		fixtures := test.FixturesServer()
+		defer fixtures.Close()
+
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if requests.Add(1) == 1 {
+				writer.Header().Set("Retry-After", "0")
+				writer.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fixtures.Config.Handler.ServeHTTP(writer, request)
+		}))
+		defer server.Close()
+
+		percipioClient, err := client.New(
+			ctx,
+			server.URL,
+			"mock",
+			"token",
+			client.WithRateLimit(100, 1),
+			client.WithMaxRetries(1),
+		)
+		require.Nil(t, err)
+
+		c := newUserBuilder(percipioClient)
+		resources, _, _, err := c.List(ctx, organizationResourceID("mock"), &pagination.Token{Size: 1})
+		require.Nil(t, err)
+		require.NotEmpty(t, resources)
+		require.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("should grant approval managers the manager entitlement", func(t *testing.T) {
+		c := newUserBuilder(nil)
+
+		employee, err := userResource(client.User{
+			Id:    "00000000-0000-0000-0000-000000000001",
+			Email: "first.last@example.com",
+			ApprovalManager: client.ApprovalManager{
+				Id:    "00000000-0000-0000-0000-000000000002",
+				Email: "manager@example.com",
+			},
+		}, nil)
+		require.Nil(t, err)
+
+		userTrait, err := resourceSdk.GetUserTrait(employee)
+		require.Nil(t, err)
+		managerId, ok := resourceSdk.GetProfileStringValue(userTrait.GetProfile(), "approval_manager_id")
+		require.True(t, ok)
+		require.Equal(t, "00000000-0000-0000-0000-000000000002", managerId)
+
+		entitlements, _, _, err := c.Entitlements(ctx, employee, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, entitlements, 1)
+
+		grants, _, _, err := c.Grants(ctx, employee, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 1)
+		require.Equal(t, "00000000-0000-0000-0000-000000000002", grants[0].Principal.Id.Resource)
+		require.Equal(t, entitlement.NewEntitlementID(employee, managerEntitlement), grants[0].Entitlement.Id)
+
+		unmanaged, err := userResource(client.User{Id: "00000000-0000-0000-0000-000000000003"}, nil)
+		require.Nil(t, err)
+		grants, _, _, err = c.Grants(ctx, unmanaged, &pagination.Token{})
+		require.Nil(t, err)
+		require.Len(t, grants, 0)
+	})
+}
+
+func TestUserDeletion(t *testing.T) {
+	ctx := context.Background()
+
+	newDeletionServer := func(statusCode int, body string, requests *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			*requests = append(*requests, request.Method+" "+request.URL.Path)
+			writer.Header().Set("Content-Type", "application/json")
+			writer.WriteHeader(statusCode)
+			_, _ = writer.Write([]byte(body))
+		}))
+	}
+
+	newBuilder := func(t *testing.T, serverUrl string, options ...client.Option) *userBuilder {
+		percipioClient, err := client.New(ctx, serverUrl, "mock", "token", options...)
+		require.Nil(t, err)
+		return newUserBuilder(percipioClient)
+	}
+
+	userId := &v2.ResourceId{ResourceType: userResourceType.Id, Resource: "00000000-0000-0000-0000-000000000001"}
+
+	t.Run("should delete a user when deletion is allowed", func(t *testing.T) {
+		var requests []string
+		server := newDeletionServer(http.StatusNoContent, "", &requests)
+		defer server.Close()
+
+		_, err := newBuilder(t, server.URL, client.WithUserDeletion(true)).Delete(ctx, userId)
+		require.Nil(t, err)
+		require.Equal(t, []string{
+			"DELETE /user-management/v1/organizations/mock/users/00000000-0000-0000-0000-000000000001",
+		}, requests)
+	})
+
+	t.Run("should refuse to delete users unless allowed", func(t *testing.T) {
+		var requests []string
+		server := newDeletionServer(http.StatusNoContent, "", &requests)
+		defer server.Close()
+
+		_, err := newBuilder(t, server.URL).Delete(ctx, userId)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.Empty(t, requests)
+	})
+
+	t.Run("should treat a missing user as deleted", func(t *testing.T) {
+		var requests []string
+		server := newDeletionServer(http.StatusNotFound, `{"message":"User not found"}`, &requests)
+		defer server.Close()
+
+		_, err := newBuilder(t, server.URL, client.WithUserDeletion(true)).Delete(ctx, userId)
+		require.Nil(t, err)
+	})
+
+	t.Run("should surface refused deletions as failed preconditions", func(t *testing.T) {
+		var requests []string
+		server := newDeletionServer(http.StatusConflict, `{"errorCode":"USER_HAS_ACTIVITY","message":"User has learning activity"}`, &requests)
+		defer server.Close()
+
+		_, err := newBuilder(t, server.URL, client.WithUserDeletion(true)).Delete(ctx, userId)
+		require.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.Contains(t, err.Error(), "User has learning activity")
+	})
+
+	t.Run("should map other rejected deletions through the api error", func(t *testing.T) {
+		for _, testCase := range []struct {
+			statusCode int
+			expected   codes.Code
+		}{
+			{http.StatusBadRequest, codes.InvalidArgument},
+			{http.StatusMethodNotAllowed, codes.InvalidArgument},
+			{http.StatusUnprocessableEntity, codes.InvalidArgument},
+			{http.StatusForbidden, codes.PermissionDenied},
+		} {
+			var requests []string
+			server := newDeletionServer(testCase.statusCode, `{"message":"Rejected"}`, &requests)
+
+			_, err := newBuilder(t, server.URL, client.WithUserDeletion(true)).Delete(ctx, userId)
+			require.Equal(t, testCase.expected, status.Code(err), testCase.statusCode)
+			server.Close()
+		}
+	})
+
+	t.Run("should only delete users", func(t *testing.T) {
+		_, err := newBuilder(t, "http://127.0.0.1:0", client.WithUserDeletion(true)).Delete(ctx, &v2.ResourceId{
+			ResourceType: roleResourceType.Id,
+			Resource:     "ADMIN",
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
 }
@@ -66,8 +66,14 @@ func FixturesServer() *httptest.Server {
 						"page=\"3\"; per_page=\"1000\"; rel=\"last\""
 					writer.Header().Set("link", linkHeader)
 					filename = "../../test/fixtures/courses0.json"
+				case strings.Contains(routeUrl, "assignments"):
+					filename = "../../test/fixtures/assignments0.json"
 				case strings.Contains(routeUrl, "users"):
 					filename = "../../test/fixtures/users0.json"
+				case strings.Contains(routeUrl, "audiences"):
+					filename = "../../test/fixtures/audiences0.json"
+				case strings.HasSuffix(request.URL.Path, "/organizations/mock"):
+					filename = "../../test/fixtures/organization0.json"
 				default:
 					// This should never happen in tests.
 					panic(fmt.Errorf("bad url: %s", routeUrl))